@@ -2,59 +2,15 @@ package read
 
 import (
 	"io"
-
-	"github.com/gopherx/base/errors"
 )
 
+// BigEndian reads big-endian values from a reader.
 type BigEndian struct {
-	// r is the reader we are consuming data from.
-	r io.Reader
-
-	// tmp is the temporary buffer used for reads.
-	tmp []byte
-
-	// Err holds the first error encountered; once an error is found all operations are no-ops.
-	Err error
-
-	// Read holds all bytes read so far.
-	Read []byte
+	source
 }
 
 func NewBigEndian(r io.Reader) *BigEndian {
-	return &BigEndian{r, make([]byte, 12), nil, make([]byte, 0, 1024)}
-}
-
-func (e *BigEndian) readTo(dest []byte) error {
-	if e.Err != nil {
-		return e.Err
-	}
-
-	rn, err := e.r.Read(dest)
-	if err != nil {
-		e.Err = err
-	}
-
-	if err == nil && rn != len(dest) {
-		e.Err = errors.DataLoss(nil, "not enough data; read: ", rn, " wanted: ", len(dest))
-	}
-
-	e.Read = append(e.Read, dest...)
-	return e.Err
-}
-
-func (e *BigEndian) read(n int) ([]byte, error) {
-	err := e.readTo(e.tmp[0:n])
-	return e.tmp, err
-}
-
-// Byte reads a byte from the buffer.
-func (e *BigEndian) Byte() byte {
-	b, err := e.read(1)
-	if err != nil {
-		return 0
-	}
-
-	return b[0]
+	return &BigEndian{newSource(r)}
 }
 
 // Uint16 reads an uin16 from the buffer.
@@ -146,13 +102,6 @@ func (e *BigEndian) Uint32x3() (uint32, uint32, uint32) {
 		(b8<<24 | b9<<16 | b10<<8 | b11)
 }
 
-// Bytes reads n bytes from the reader.
-func (e *BigEndian) Bytes(n int) []byte {
-	tmp := make([]byte, n)
-	e.readTo(tmp)
-	return tmp
-}
-
 // Uint16 reads an uin16 from the buffer.
 func Uint16(b []byte) uint16 {
 	b0 := uint16(b[0])
@@ -0,0 +1,130 @@
+package read
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gopherx/base/errors"
+	"github.com/gopherx/base/errors/codes"
+)
+
+var (
+	_ Reader = (*BigEndian)(nil)
+	_ Reader = (*LittleEndian)(nil)
+)
+
+// values reads one of each value; parsers are written once for both orders.
+func values(r Reader) []interface{} {
+	v := []interface{}{r.Byte(), r.Uint16(), r.Uint32(), r.Uint64(), r.Int64()}
+	a, b, c := r.Uint32x3()
+	return append(v, a, b, c, r.Bytes(2))
+}
+
+func TestReaders(t *testing.T) {
+	data := []byte{
+		0x01,
+		0x01, 0x02,
+		0x01, 0x02, 0x03, 0x04,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFE,
+		0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x03,
+		0xAB, 0xCD,
+	}
+
+	tests := []struct {
+		name string
+		r    Reader
+		want []interface{}
+	}{
+		{"BigEndian", NewBigEndian(bytes.NewReader(data)), []interface{}{
+			byte(0x01), uint16(0x0102), uint32(0x01020304), uint64(0x0102030405060708), int64(-2),
+			uint32(0x01000000), uint32(0x00000002), uint32(0x00000003), []byte{0xAB, 0xCD},
+		}},
+		{"LittleEndian", NewLittleEndian(bytes.NewReader(data)), []interface{}{
+			byte(0x01), uint16(0x0201), uint32(0x04030201), uint64(0x0807060504030201), int64(-72057594037927937),
+			uint32(0x00000001), uint32(0x02000000), uint32(0x03000000), []byte{0xAB, 0xCD},
+		}},
+	}
+
+	for _, tc := range tests {
+		got := values(tc.r)
+		if tc.r.Failure() != nil {
+			t.Fatalf("%s: %v", tc.name, tc.r.Failure())
+		}
+
+		for i := range tc.want {
+			if !equal(got[i], tc.want[i]) {
+				t.Errorf("%s: value %d; got:%#v want:%#v", tc.name, i, got[i], tc.want[i])
+			}
+		}
+
+		// ...the reader is drained; reads fail and stay failed.
+		if tc.r.Uint16() != 0 || tc.r.Failure() == nil || tc.r.Byte() != 0 {
+			t.Errorf("%s: read past the end", tc.name)
+		}
+	}
+}
+
+func equal(a, b interface{}) bool {
+	if ab, ok := a.([]byte); ok {
+		return bytes.Equal(ab, b.([]byte))
+	}
+	return a == b
+}
+
+func TestShortRead(t *testing.T) {
+	r := NewLittleEndian(bytes.NewReader([]byte{0x01, 0x02}))
+	if r.Uint32() != 0 || errors.Code(r.Err) != codes.DataLoss {
+		t.Errorf("got:%v", r.Err)
+	}
+
+	if r.Byte() != 0 || r.Failure() != r.Err {
+		t.Error("failure not sticky")
+	}
+}
+
+func TestStreams(t *testing.T) {
+	data := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C}
+
+	streams := map[string]func() io.Reader{
+		"OneByteReader": func() io.Reader { return iotest.OneByteReader(bytes.NewReader(data)) },
+		"HalfReader":    func() io.Reader { return iotest.HalfReader(bytes.NewReader(data)) },
+		"DataErrReader": func() io.Reader { return iotest.DataErrReader(bytes.NewReader(data)) },
+	}
+
+	for name, stream := range streams {
+		r := NewBigEndian(stream())
+		if got := r.Uint64(); got != 0x0102030405060708 {
+			t.Errorf("%s: got:%#x err:%v", name, got, r.Err)
+		}
+		if got := r.Uint32(); got != 0x090A0B0C {
+			t.Errorf("%s: got:%#x err:%v", name, got, r.Err)
+		}
+		if !bytes.Equal(r.Read, data) {
+			t.Errorf("%s: read; got:%x", name, r.Read)
+		}
+
+		// ...the data ended cleanly at a value boundary.
+		if r.Byte() != 0 || r.Err != io.EOF {
+			t.Errorf("%s: got:%v want:EOF", name, r.Err)
+		}
+
+		// ...the data ended within a value.
+		r = NewBigEndian(stream())
+		r.Uint64()
+		r.Uint64()
+		if errors.Code(r.Err) != codes.DataLoss || errors.Cause(r.Err) != io.ErrUnexpectedEOF {
+			t.Errorf("%s: got:%v want:DataLoss", name, r.Err)
+		}
+		if !bytes.Equal(r.Read, data) {
+			t.Errorf("%s: read; got:%x", name, r.Read)
+		}
+	}
+
+	r := NewLittleEndian(iotest.ErrReader(io.ErrClosedPipe))
+	if r.Uint16() != 0 || r.Err != io.ErrClosedPipe {
+		t.Errorf("got:%v", r.Err)
+	}
+}
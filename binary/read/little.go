@@ -0,0 +1,90 @@
+package read
+
+import (
+	"io"
+)
+
+// LittleEndian reads little-endian values from a reader.
+type LittleEndian struct {
+	source
+}
+
+func NewLittleEndian(r io.Reader) *LittleEndian {
+	return &LittleEndian{newSource(r)}
+}
+
+// Uint16 reads an uin16 from the buffer.
+func (e *LittleEndian) Uint16() uint16 {
+	b, err := e.read(2)
+	if err != nil {
+		return 0
+	}
+
+	b0 := uint16(b[0])
+	b1 := uint16(b[1])
+	return b0 | b1<<8
+}
+
+// Uint32 reads an uint32 from the buffer.
+func (e *LittleEndian) Uint32() uint32 {
+	b, err := e.read(4)
+	if err != nil {
+		return 0
+	}
+
+	b0 := uint32(b[0])
+	b1 := uint32(b[1])
+	b2 := uint32(b[2])
+	b3 := uint32(b[3])
+	return b0 | b1<<8 | b2<<16 | b3<<24
+}
+
+// Uint64 reads an Uint64 from the buffer.
+func (e *LittleEndian) Uint64() uint64 {
+	b, err := e.read(8)
+	if err != nil {
+		return 0
+	}
+
+	b0 := uint64(b[0])
+	b1 := uint64(b[1])
+	b2 := uint64(b[2])
+	b3 := uint64(b[3])
+	b4 := uint64(b[4])
+	b5 := uint64(b[5])
+	b6 := uint64(b[6])
+	b7 := uint64(b[7])
+	return b0 | b1<<8 | b2<<16 | b3<<24 | b4<<32 | b5<<40 | b6<<48 | b7<<56
+}
+
+// Int64 reads an Int64 from the buffer.
+func (e *LittleEndian) Int64() int64 {
+	return int64(e.Uint64())
+}
+
+// Uint32x3 reads three uint32 from the buffer.
+func (e *LittleEndian) Uint32x3() (uint32, uint32, uint32) {
+	b, err := e.read(12)
+	if err != nil {
+		return 0, 0, 0
+	}
+
+	b0 := uint32(b[0])
+	b1 := uint32(b[1])
+	b2 := uint32(b[2])
+	b3 := uint32(b[3])
+
+	b4 := uint32(b[4])
+	b5 := uint32(b[5])
+	b6 := uint32(b[6])
+	b7 := uint32(b[7])
+
+	b8 := uint32(b[8])
+	b9 := uint32(b[9])
+	b10 := uint32(b[10])
+	b11 := uint32(b[11])
+
+	return (b0 | b1<<8 | b2<<16 | b3<<24),
+		(b4 | b5<<8 | b6<<16 | b7<<24),
+		(b8 | b9<<8 | b10<<16 | b11<<24)
+}
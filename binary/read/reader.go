@@ -0,0 +1,88 @@
+package read
+
+import (
+	"io"
+
+	"github.com/gopherx/base/errors"
+)
+
+// Reader reads values in a byte order; BigEndian and LittleEndian implement it so parsers can be
+// written once for both orders. Reads are sticky: once a read fails all following reads return
+// zero values and Failure returns the first error. Failure is io.EOF if the data ended cleanly
+// before a value and a codes.DataLoss error if it ended within one.
+type Reader interface {
+	Byte() byte
+	Uint16() uint16
+	Uint32() uint32
+	Uint64() uint64
+	Int64() int64
+	Uint32x3() (uint32, uint32, uint32)
+	Bytes(n int) []byte
+
+	// Failure returns the first error encountered (or nil if none).
+	Failure() error
+}
+
+// source holds the reader and state shared by the byte orders.
+type source struct {
+	// r is the reader we are consuming data from.
+	r io.Reader
+
+	// tmp is the temporary buffer used for reads.
+	tmp []byte
+
+	// Err holds the first error encountered; once an error is found all operations are no-ops.
+	Err error
+
+	// Read holds all bytes read so far.
+	Read []byte
+}
+
+func newSource(r io.Reader) source {
+	return source{r, make([]byte, 12), nil, make([]byte, 0, 1024)}
+}
+
+// readTo fills dest; short reads are retried like io.ReadFull. Running out of data before the
+// first byte of dest leaves io.EOF in Err (a clean end at a value boundary); running out after it
+// is a codes.DataLoss error.
+func (e *source) readTo(dest []byte) error {
+	if e.Err != nil {
+		return e.Err
+	}
+
+	rn, err := io.ReadFull(e.r, dest)
+	e.Err = err
+	if err == io.ErrUnexpectedEOF {
+		e.Err = errors.DataLoss(err, "not enough data", errors.Field("read", rn), errors.Field("wanted", len(dest)))
+	}
+
+	e.Read = append(e.Read, dest[:rn]...)
+	return e.Err
+}
+
+func (e *source) read(n int) ([]byte, error) {
+	err := e.readTo(e.tmp[0:n])
+	return e.tmp, err
+}
+
+// Byte reads a byte from the buffer.
+func (e *source) Byte() byte {
+	b, err := e.read(1)
+	if err != nil {
+		return 0
+	}
+
+	return b[0]
+}
+
+// Bytes reads n bytes from the reader.
+func (e *source) Bytes(n int) []byte {
+	tmp := make([]byte, n)
+	e.readTo(tmp)
+	return tmp
+}
+
+// Failure returns Err.
+func (e *source) Failure() error {
+	return e.Err
+}
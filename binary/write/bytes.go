@@ -6,6 +6,24 @@ import (
 	"github.com/golang/glog"
 )
 
+// Writer writes values in a byte order; BigEndian and LittleEndian implement it so encoders can
+// be written once for both orders. Writes are sticky: once a write fails all following writes are
+// no-ops and Failure returns the first error.
+type Writer interface {
+	Byte(v byte)
+	Uint16(v uint16)
+	Uint16At(offset int, v uint16)
+	Uint32(v uint32)
+	Uint64(v uint64)
+	Int64(v int64)
+	Uint32x3(v0, v1, v2 uint32)
+	Bytes(bytes []byte)
+
+	// Failure returns the first error encountered (or nil if none).
+	Failure() error
+}
+
+// BigEndian writes big-endian values to Dest.
 type BigEndian struct {
 	Dest   []byte
 	Offset int
@@ -16,12 +34,17 @@ func (b *BigEndian) fail(op string, v interface{}) {
 	b.Err = errors.OutOfRange(nil, op, v)
 }
 
+// Failure returns Err.
+func (b *BigEndian) Failure() error {
+	return b.Err
+}
+
 func (b *BigEndian) Byte(v byte) {
 	if b.Err != nil {
 		return
 	}
 
-	if b.Offset+1 >= len(b.Dest) {
+	if b.Offset+1 > len(b.Dest) {
 		b.fail("Byte:", v)
 		return
 	}
@@ -35,7 +58,7 @@ func (b *BigEndian) Uint16(v uint16) {
 		return
 	}
 
-	if b.Offset+2 >= len(b.Dest) {
+	if b.Offset+2 > len(b.Dest) {
 		b.fail("Uint16:", v)
 		return
 	}
@@ -56,7 +79,7 @@ func (b *BigEndian) Uint16At(offset int, v uint16) {
 		return
 	}
 
-	if offset+2 >= len(b.Dest) {
+	if offset < 0 || offset+2 > len(b.Dest) {
 		b.fail("Uint16At:", v)
 		return
 	}
@@ -74,7 +97,7 @@ func (b *BigEndian) Uint32(v uint32) {
 		return
 	}
 
-	if b.Offset+4 >= len(b.Dest) {
+	if b.Offset+4 > len(b.Dest) {
 		b.fail("Uint32:", v)
 		return
 	}
@@ -99,7 +122,7 @@ func (b *BigEndian) Uint64(v uint64) {
 		return
 	}
 
-	if b.Offset+8 >= len(b.Dest) {
+	if b.Offset+8 > len(b.Dest) {
 		b.fail("Uint64:", v)
 		return
 	}
@@ -127,12 +150,40 @@ func (b *BigEndian) Uint64(v uint64) {
 	b.Offset += 8
 }
 
+func (b *BigEndian) Int64(v int64) {
+	if b.Err != nil {
+		return
+	}
+
+	if b.Offset+8 > len(b.Dest) {
+		b.fail("Int64:", v)
+		return
+	}
+
+	b.Uint64(uint64(v))
+}
+
+func (b *BigEndian) Uint32x3(v0, v1, v2 uint32) {
+	if b.Err != nil {
+		return
+	}
+
+	if b.Offset+12 > len(b.Dest) {
+		b.fail("Uint32x3:", v0)
+		return
+	}
+
+	b.Uint32(v0)
+	b.Uint32(v1)
+	b.Uint32(v2)
+}
+
 func (b *BigEndian) Bytes(bytes []byte) {
 	if b.Err != nil {
 		return
 	}
 
-	if b.Offset+len(bytes) >= len(b.Dest) {
+	if b.Offset+len(bytes) > len(b.Dest) {
 		b.fail("Bytes:", len(bytes))
 		return
 	}
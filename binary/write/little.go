@@ -0,0 +1,150 @@
+package write
+
+import (
+	"github.com/gopherx/base/errors"
+)
+
+// LittleEndian writes little-endian values to Dest.
+type LittleEndian struct {
+	Dest   []byte
+	Offset int
+	Err    error
+}
+
+func (b *LittleEndian) fail(op string, v interface{}) {
+	b.Err = errors.OutOfRange(nil, op, v)
+}
+
+// Failure returns Err.
+func (b *LittleEndian) Failure() error {
+	return b.Err
+}
+
+func (b *LittleEndian) Byte(v byte) {
+	if b.Err != nil {
+		return
+	}
+
+	if b.Offset+1 > len(b.Dest) {
+		b.fail("Byte:", v)
+		return
+	}
+
+	b.Dest[b.Offset] = v
+	b.Offset += 1
+}
+
+func (b *LittleEndian) Uint16(v uint16) {
+	if b.Err != nil {
+		return
+	}
+
+	if b.Offset+2 > len(b.Dest) {
+		b.fail("Uint16:", v)
+		return
+	}
+
+	b.Uint16At(b.Offset, v)
+	b.Offset += 2
+}
+
+func (b *LittleEndian) Uint16At(offset int, v uint16) {
+	if b.Err != nil {
+		return
+	}
+
+	if offset < 0 || offset+2 > len(b.Dest) {
+		b.fail("Uint16At:", v)
+		return
+	}
+
+	dest := b.Dest
+	dest[offset] = byte(v)
+	dest[offset+1] = byte(v >> 8)
+}
+
+func (b *LittleEndian) Uint32(v uint32) {
+	if b.Err != nil {
+		return
+	}
+
+	if b.Offset+4 > len(b.Dest) {
+		b.fail("Uint32:", v)
+		return
+	}
+
+	dest := b.Dest
+	offset := b.Offset
+	dest[offset] = byte(v)
+	dest[offset+1] = byte(v >> 8)
+	dest[offset+2] = byte(v >> 16)
+	dest[offset+3] = byte(v >> 24)
+
+	b.Offset += 4
+}
+
+func (b *LittleEndian) Uint64(v uint64) {
+	if b.Err != nil {
+		return
+	}
+
+	if b.Offset+8 > len(b.Dest) {
+		b.fail("Uint64:", v)
+		return
+	}
+
+	dest := b.Dest
+	offset := b.Offset
+	dest[offset] = byte(v)
+	dest[offset+1] = byte(v >> 8)
+	dest[offset+2] = byte(v >> 16)
+	dest[offset+3] = byte(v >> 24)
+	dest[offset+4] = byte(v >> 32)
+	dest[offset+5] = byte(v >> 40)
+	dest[offset+6] = byte(v >> 48)
+	dest[offset+7] = byte(v >> 56)
+
+	b.Offset += 8
+}
+
+func (b *LittleEndian) Int64(v int64) {
+	if b.Err != nil {
+		return
+	}
+
+	if b.Offset+8 > len(b.Dest) {
+		b.fail("Int64:", v)
+		return
+	}
+
+	b.Uint64(uint64(v))
+}
+
+func (b *LittleEndian) Uint32x3(v0, v1, v2 uint32) {
+	if b.Err != nil {
+		return
+	}
+
+	if b.Offset+12 > len(b.Dest) {
+		b.fail("Uint32x3:", v0)
+		return
+	}
+
+	b.Uint32(v0)
+	b.Uint32(v1)
+	b.Uint32(v2)
+}
+
+func (b *LittleEndian) Bytes(bytes []byte) {
+	if b.Err != nil {
+		return
+	}
+
+	if b.Offset+len(bytes) > len(b.Dest) {
+		b.fail("Bytes:", len(bytes))
+		return
+	}
+
+	copy(b.Dest[b.Offset:], bytes)
+	b.Offset += len(bytes)
+}
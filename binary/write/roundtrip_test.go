@@ -0,0 +1,125 @@
+package write
+
+import (
+	"math/bits"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/gopherx/base/binary/read"
+)
+
+var (
+	_ Writer = (*BigEndian)(nil)
+	_ Writer = (*LittleEndian)(nil)
+)
+
+// record is one of each value; recordSize is its encoded size.
+type record struct {
+	b          byte
+	u16        uint16
+	u32        uint32
+	u64        uint64
+	i64        int64
+	x0, x1, x2 uint32
+	raw        []byte
+}
+
+const recordSize = 1 + 2 + 4 + 8 + 8 + 12 + 5
+
+func randomRecord(rnd *rand.Rand) record {
+	raw := make([]byte, 5)
+	rnd.Read(raw)
+	return record{
+		byte(rnd.Uint32()), uint16(rnd.Uint32()), rnd.Uint32(), rnd.Uint64(), int64(rnd.Uint64()),
+		rnd.Uint32(), rnd.Uint32(), rnd.Uint32(), raw,
+	}
+}
+
+// swapped returns the record as read in the other byte order.
+func (r record) swapped() record {
+	return record{
+		r.b, bits.ReverseBytes16(r.u16), bits.ReverseBytes32(r.u32), bits.ReverseBytes64(r.u64), int64(bits.ReverseBytes64(uint64(r.i64))),
+		bits.ReverseBytes32(r.x0), bits.ReverseBytes32(r.x1), bits.ReverseBytes32(r.x2), r.raw,
+	}
+}
+
+func (r record) equal(o record) bool {
+	return r.b == o.b && r.u16 == o.u16 && r.u32 == o.u32 && r.u64 == o.u64 && r.i64 == o.i64 &&
+		r.x0 == o.x0 && r.x1 == o.x1 && r.x2 == o.x2 && string(r.raw) == string(o.raw)
+}
+
+func encode(w Writer, r record) {
+	w.Byte(r.b)
+	w.Uint16(0)
+	w.Uint32(r.u32)
+	w.Uint64(r.u64)
+	w.Int64(r.i64)
+	w.Uint32x3(r.x0, r.x1, r.x2)
+	w.Bytes(r.raw)
+
+	// ...patched like a length field.
+	w.Uint16At(1, r.u16)
+}
+
+func decode(rd read.Reader) record {
+	var r record
+	r.b = rd.Byte()
+	r.u16 = rd.Uint16()
+	r.u32 = rd.Uint32()
+	r.u64 = rd.Uint64()
+	r.i64 = rd.Int64()
+	r.x0, r.x1, r.x2 = rd.Uint32x3()
+	r.raw = rd.Bytes(5)
+	return r
+}
+
+func TestRoundTrip(t *testing.T) {
+	writers := map[string]func([]byte) Writer{
+		"BigEndian":    func(d []byte) Writer { return &BigEndian{Dest: d} },
+		"LittleEndian": func(d []byte) Writer { return &LittleEndian{Dest: d} },
+	}
+	readers := map[string]func([]byte) read.Reader{
+		"BigEndian":    func(d []byte) read.Reader { return read.NewBigEndian(strings.NewReader(string(d))) },
+		"LittleEndian": func(d []byte) read.Reader { return read.NewLittleEndian(strings.NewReader(string(d))) },
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		want := randomRecord(rnd)
+
+		for wname, newWriter := range writers {
+			dest := make([]byte, recordSize)
+			w := newWriter(dest)
+			encode(w, want)
+			if w.Failure() != nil {
+				t.Fatalf("%s: %v", wname, w.Failure())
+			}
+
+			for rname, newReader := range readers {
+				rd := newReader(dest)
+				got := decode(rd)
+				if rd.Failure() != nil {
+					t.Fatalf("%s -> %s: %v", wname, rname, rd.Failure())
+				}
+
+				expected := want
+				if wname != rname {
+					expected = want.swapped()
+				}
+				if !got.equal(expected) {
+					t.Fatalf("%s -> %s: got:%+v want:%+v", wname, rname, got, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestWriterFull(t *testing.T) {
+	for _, w := range []Writer{&BigEndian{Dest: make([]byte, recordSize-1)}, &LittleEndian{Dest: make([]byte, recordSize-1)}} {
+		encode(w, record{raw: make([]byte, 5)})
+		if w.Failure() == nil {
+			t.Errorf("%T: wrote past the end", w)
+		}
+	}
+}
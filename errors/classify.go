@@ -0,0 +1,96 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+// Classifier returns the code of an error from another package; false if the error is unknown
+// to the classifier. The error may be wrapped, use errors.Is/As to check it.
+type Classifier func(err error) (codes.Code, bool)
+
+var (
+	// classifiers holds the registered []*Classifier; copied on write. Pointers identify the
+	// registrations for removal.
+	classifiers   atomic.Value
+	classifiersMu sync.Mutex
+)
+
+// foreign maps errors from the standard library to codes.
+var foreign = []struct {
+	err  error
+	code codes.Code
+}{
+	{context.Canceled, codes.Canceled},
+	{context.DeadlineExceeded, codes.DeadlineExceeded},
+	{os.ErrDeadlineExceeded, codes.DeadlineExceeded},
+	{io.EOF, codes.OutOfRange},
+	{io.ErrUnexpectedEOF, codes.DataLoss},
+	{fs.ErrNotExist, codes.NotFound},
+	{fs.ErrExist, codes.AlreadyExists},
+	{fs.ErrPermission, codes.PermissionDenied},
+	{fs.ErrInvalid, codes.InvalidArgument},
+	{fs.ErrClosed, codes.FailedPrecondition},
+	{errors.ErrUnsupported, codes.Unimplemented},
+}
+
+func classifyStdlib(err error) (codes.Code, bool) {
+	for _, f := range foreign {
+		if errors.Is(err, f.err) {
+			return f.code, true
+		}
+	}
+	return codes.Unknown, false
+}
+
+func init() {
+	RegisterClassifier(classifyStdlib)
+}
+
+// RegisterClassifier registers a classifier used by Code for errors not from this package,
+// e.g. to map the errors of a database driver. Classifiers registered later are tried first so
+// they can override the mapping of the standard library errors. The returned func removes the
+// classifier again; tests registering classifiers should defer it.
+func RegisterClassifier(c Classifier) (unregister func()) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+
+	reg := &c
+	old, _ := classifiers.Load().([]*Classifier)
+	cs := make([]*Classifier, 0, len(old)+1)
+	cs = append(cs, reg)
+	cs = append(cs, old...)
+	classifiers.Store(cs)
+
+	return func() {
+		classifiersMu.Lock()
+		defer classifiersMu.Unlock()
+
+		old, _ := classifiers.Load().([]*Classifier)
+		cs := make([]*Classifier, 0, len(old))
+		for _, r := range old {
+			if r != reg {
+				cs = append(cs, r)
+			}
+		}
+		classifiers.Store(cs)
+	}
+}
+
+// classify returns the code of an error from another package; codes.Unknown if not known.
+func classify(err error) codes.Code {
+	cs, _ := classifiers.Load().([]*Classifier)
+	for _, c := range cs {
+		if code, ok := (*c)(err); ok {
+			return code
+		}
+	}
+	return codes.Unknown
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+type driverError struct {
+	state string
+}
+
+func (e *driverError) Error() string {
+	return "driver: " + e.state
+}
+
+func TestClassify(t *testing.T) {
+	_, statErr := os.Stat("/does/not/exist")
+
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{context.Canceled, codes.Canceled},
+		{fmt.Errorf("call: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{io.EOF, codes.OutOfRange},
+		{io.ErrUnexpectedEOF, codes.DataLoss},
+		{statErr, codes.NotFound},
+		{os.ErrExist, codes.AlreadyExists},
+		{os.ErrPermission, codes.PermissionDenied},
+		{errors.New("who knows"), codes.Unknown},
+		{Internal(context.Canceled, "wrapped"), codes.Internal},
+		{&driverError{"40001"}, codes.Aborted},
+		{fmt.Errorf("tx: %w", &driverError{"23505"}), codes.AlreadyExists},
+		{&driverError{"XX000"}, codes.Unknown},
+	}
+
+	unregister := RegisterClassifier(func(err error) (codes.Code, bool) {
+		var de *driverError
+		if !errors.As(err, &de) {
+			return codes.Unknown, false
+		}
+
+		switch de.state {
+		case "40001":
+			return codes.Aborted, true
+		case "23505":
+			return codes.AlreadyExists, true
+		}
+		return codes.Unknown, false
+	})
+
+	for i, c := range tests {
+		if Code(c.err) != c.code {
+			t.Errorf("%d: %v; got:%v want:%v", i, c.err, Code(c.err), c.code)
+		}
+	}
+
+	unregister()
+	if Code(&driverError{"40001"}) != codes.Unknown || Code(io.EOF) != codes.OutOfRange {
+		t.Error("classifier not unregistered")
+	}
+}
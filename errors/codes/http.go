@@ -0,0 +1,69 @@
+package codes
+
+import (
+	"net/http"
+)
+
+// httpStatus maps codes to HTTP statuses; see google.rpc.Code for the canonical mapping.
+var httpStatus = [...]int{
+	OK:                 http.StatusOK,
+	Canceled:           499, // Client Closed Request; not in net/http.
+	Unknown:            http.StatusInternalServerError,
+	InvalidArgument:    http.StatusBadRequest,
+	DeadlineExceeded:   http.StatusGatewayTimeout,
+	NotFound:           http.StatusNotFound,
+	AlreadyExists:      http.StatusConflict,
+	PermissionDenied:   http.StatusForbidden,
+	ResourceExhausted:  http.StatusTooManyRequests,
+	FailedPrecondition: http.StatusBadRequest,
+	Aborted:            http.StatusConflict,
+	OutOfRange:         http.StatusBadRequest,
+	Unimplemented:      http.StatusNotImplemented,
+	Internal:           http.StatusInternalServerError,
+	Unavailable:        http.StatusServiceUnavailable,
+	DataLoss:           http.StatusInternalServerError,
+	Unauthenticated:    http.StatusUnauthorized,
+}
+
+// HTTPStatus returns the HTTP status for the code. Unknown codes map to 500.
+func HTTPStatus(c Code) int {
+	if int(c) >= len(httpStatus) {
+		return http.StatusInternalServerError
+	}
+	return httpStatus[c]
+}
+
+// FromHTTPStatus returns the code for the HTTP status. Several codes share a status so this is
+// not the inverse of HTTPStatus; the most general code is picked. Other 2xx statuses map to OK
+// and statuses without a mapping to Unknown.
+func FromHTTPStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return InvalidArgument
+	case http.StatusUnauthorized:
+		return Unauthenticated
+	case http.StatusForbidden:
+		return PermissionDenied
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return AlreadyExists
+	case http.StatusTooManyRequests:
+		return ResourceExhausted
+	case 499:
+		return Canceled
+	case http.StatusInternalServerError:
+		return Internal
+	case http.StatusNotImplemented:
+		return Unimplemented
+	case http.StatusServiceUnavailable:
+		return Unavailable
+	case http.StatusGatewayTimeout:
+		return DeadlineExceeded
+	}
+
+	if status >= 200 && status < 300 {
+		return OK
+	}
+	return Unknown
+}
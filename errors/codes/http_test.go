@@ -0,0 +1,42 @@
+package codes
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	// Codes with a status of their own must survive the round trip.
+	tests := []Code{
+		OK,
+		Canceled,
+		InvalidArgument,
+		DeadlineExceeded,
+		NotFound,
+		AlreadyExists,
+		PermissionDenied,
+		ResourceExhausted,
+		Unimplemented,
+		Internal,
+		Unavailable,
+		Unauthenticated,
+	}
+
+	for _, c := range tests {
+		if got := FromHTTPStatus(HTTPStatus(c)); got != c {
+			t.Errorf("%v: got:%v status:%d", c, got, HTTPStatus(c))
+		}
+	}
+
+	if HTTPStatus(Code(100)) != http.StatusInternalServerError {
+		t.Error(HTTPStatus(Code(100)))
+	}
+
+	if FromHTTPStatus(http.StatusNoContent) != OK {
+		t.Error(FromHTTPStatus(http.StatusNoContent))
+	}
+
+	if FromHTTPStatus(http.StatusTeapot) != Unknown {
+		t.Error(FromHTTPStatus(http.StatusTeapot))
+	}
+}
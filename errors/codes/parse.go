@@ -0,0 +1,93 @@
+package codes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxCode is the last known code.
+const maxCode = Unauthenticated
+
+// names maps the accepted names to codes; see Parse.
+var names = map[string]Code{
+	// ...gRPC spells it the British way.
+	"CANCELLED": Canceled,
+}
+
+func init() {
+	for c := OK; c <= maxCode; c++ {
+		names[c.String()] = c
+		names[upperSnake(c.String())] = c
+	}
+}
+
+// upperSnake converts NotFound to NOT_FOUND.
+func upperSnake(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' && name[i-1] >= 'a' && name[i-1] <= 'z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
+
+// Parse returns the code with the name. Accepts the names of the constants (NotFound), the
+// canonical gRPC names (NOT_FOUND) and numbers (5).
+func Parse(name string) (Code, error) {
+	if c, ok := names[name]; ok {
+		return c, nil
+	}
+
+	if n, err := strconv.ParseUint(name, 10, 32); err == nil {
+		return Code(n), nil
+	}
+
+	return Unknown, fmt.Errorf("codes: unknown code %q", name)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface; known codes are marshaled by
+// name (NotFound), other codes by number.
+func (i Code) MarshalText() ([]byte, error) {
+	if i > maxCode {
+		return strconv.AppendUint(nil, uint64(i), 10), nil
+	}
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface; see Parse.
+func (i *Code) UnmarshalText(text []byte) error {
+	c, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*i = c
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface; codes are marshaled as strings, see
+// MarshalText.
+func (i Code) MarshalJSON() ([]byte, error) {
+	text, _ := i.MarshalText()
+	return strconv.AppendQuote(nil, string(text)), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Accepts strings (see Parse) and
+// numbers so codes marshaled as plain numbers can still be read.
+func (i *Code) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	return i.UnmarshalText([]byte(s))
+}
+
+// Set implements the flag.Value interface; see Parse.
+func (i *Code) Set(name string) error {
+	return i.UnmarshalText([]byte(name))
+}
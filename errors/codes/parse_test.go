@@ -0,0 +1,91 @@
+package codes
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		want Code
+	}{
+		{"OK", OK},
+		{"NotFound", NotFound},
+		{"NOT_FOUND", NotFound},
+		{"DEADLINE_EXCEEDED", DeadlineExceeded},
+		{"Canceled", Canceled},
+		{"CANCELED", Canceled},
+		{"CANCELLED", Canceled},
+		{"UNAUTHENTICATED", Unauthenticated},
+		{"5", NotFound},
+		{"42", Code(42)},
+	}
+
+	for _, tc := range tests {
+		got, err := Parse(tc.name)
+		if err != nil || got != tc.want {
+			t.Errorf("%q: got:%v,%v want:%v", tc.name, got, err, tc.want)
+		}
+	}
+
+	for _, name := range []string{"", "not_found", "Nope", "-1"} {
+		if _, err := Parse(name); err == nil {
+			t.Errorf("%q: parsed", name)
+		}
+	}
+
+	for c := OK; c <= maxCode; c++ {
+		if got, err := Parse(c.String()); err != nil || got != c {
+			t.Errorf("%v: got:%v,%v", c, got, err)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type msg struct {
+		Code Code `json:"code"`
+	}
+
+	b, err := json.Marshal(msg{NotFound})
+	if err != nil || string(b) != `{"code":"NotFound"}` {
+		t.Errorf("got:%s,%v", b, err)
+	}
+
+	b, err = json.Marshal(msg{Code(42)})
+	if err != nil || string(b) != `{"code":"42"}` {
+		t.Errorf("got:%s,%v", b, err)
+	}
+
+	for _, in := range []string{`{"code":"NotFound"}`, `{"code":"NOT_FOUND"}`, `{"code":5}`} {
+		var m msg
+		if err := json.Unmarshal([]byte(in), &m); err != nil || m.Code != NotFound {
+			t.Errorf("%s: got:%v,%v", in, m.Code, err)
+		}
+	}
+
+	var m msg
+	if err := json.Unmarshal([]byte(`{"code":"Nope"}`), &m); err == nil {
+		t.Error("unknown code unmarshaled")
+	}
+
+	var keys map[Code]int
+	if err := json.Unmarshal([]byte(`{"NOT_FOUND":1}`), &keys); err != nil || keys[NotFound] != 1 {
+		t.Errorf("got:%v,%v", keys, err)
+	}
+}
+
+func TestFlag(t *testing.T) {
+	c := Unavailable
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&c, "code", "code to return")
+
+	if err := fs.Parse([]string{"-code=PERMISSION_DENIED"}); err != nil || c != PermissionDenied {
+		t.Errorf("got:%v,%v", c, err)
+	}
+
+	if err := fs.Parse([]string{"-code=nope"}); err == nil {
+		t.Error("unknown code parsed")
+	}
+}
@@ -0,0 +1,34 @@
+package codes
+
+// Scope tells at which level an operation failing with a code may be retried.
+type Scope int
+
+const (
+	// NoRetry means the operation should not be retried until the system or the request
+	// has been fixed.
+	NoRetry Scope = iota
+
+	// RetryCall means the failing call can be retried as is, preferably with a backoff.
+	RetryCall
+
+	// RetryTransaction means the operation should be retried at a higher level, e.g. by
+	// restarting a read-modify-write sequence.
+	RetryTransaction
+)
+
+// RetryScope returns the level at which an operation failing with the code may be retried;
+// see the litmus test for FailedPrecondition, Aborted and Unavailable.
+func RetryScope(c Code) Scope {
+	switch c {
+	case Unavailable, ResourceExhausted:
+		return RetryCall
+	case Aborted:
+		return RetryTransaction
+	}
+	return NoRetry
+}
+
+// IsRetryable returns true if an operation failing with the code may be retried at some level.
+func IsRetryable(c Code) bool {
+	return RetryScope(c) != NoRetry
+}
@@ -0,0 +1,37 @@
+package codes
+
+import (
+	"testing"
+)
+
+func TestRetryScope(t *testing.T) {
+	tests := map[Code]Scope{
+		OK:                 NoRetry,
+		Canceled:           NoRetry,
+		Unknown:            NoRetry,
+		InvalidArgument:    NoRetry,
+		DeadlineExceeded:   NoRetry,
+		NotFound:           NoRetry,
+		AlreadyExists:      NoRetry,
+		PermissionDenied:   NoRetry,
+		ResourceExhausted:  RetryCall,
+		FailedPrecondition: NoRetry,
+		Aborted:            RetryTransaction,
+		OutOfRange:         NoRetry,
+		Unimplemented:      NoRetry,
+		Internal:           NoRetry,
+		Unavailable:        RetryCall,
+		DataLoss:           NoRetry,
+		Unauthenticated:    NoRetry,
+	}
+
+	for c, want := range tests {
+		if got := RetryScope(c); got != want {
+			t.Errorf("%v: got:%v want:%v", c, got, want)
+		}
+	}
+
+	if IsRetryable(NotFound) || !IsRetryable(Unavailable) || !IsRetryable(Aborted) {
+		t.Error("IsRetryable")
+	}
+}
@@ -0,0 +1,136 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// Detail is a typed payload attached to an error; pass it as one of the args to the constructors.
+// Details are kept apart from the args, retrieved with Details and serialized with the error;
+// pointers to details are kept as the values they point to.
+// Unlike args they are meant for clients: the gRPC bridge (grpcstatus) sends them along with the
+// public message, so never put internal information in them. HTTP problem details (httperr)
+// don't include them.
+type Detail interface {
+	detailType() string
+}
+
+// derefDetail returns the detail a pointer detail (e.g. *BadRequest) points to so details are
+// always kept as values; nil for nil pointers.
+func derefDetail(d Detail) Detail {
+	v := reflect.ValueOf(d)
+	if v.Kind() != reflect.Pointer {
+		return d
+	}
+	if v.IsNil() {
+		return nil
+	}
+	return v.Elem().Interface().(Detail)
+}
+
+// FieldViolation describes a single bad field of a request.
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// BadRequest lists the bad fields of a request; use with codes.InvalidArgument.
+type BadRequest struct {
+	Violations []FieldViolation `json:"violations"`
+}
+
+// RetryInfo tells clients how long to wait before retrying; use with codes.Unavailable.
+type RetryInfo struct {
+	Delay time.Duration `json:"delay"`
+}
+
+// QuotaViolation describes a single exceeded quota.
+type QuotaViolation struct {
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// QuotaFailure lists the exceeded quotas; use with codes.ResourceExhausted.
+type QuotaFailure struct {
+	Violations []QuotaViolation `json:"violations"`
+}
+
+// PreconditionViolation describes a single failed precondition.
+type PreconditionViolation struct {
+	Type        string `json:"type"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// PreconditionFailure lists the failed preconditions; use with codes.FailedPrecondition.
+type PreconditionFailure struct {
+	Violations []PreconditionViolation `json:"violations"`
+}
+
+func (BadRequest) detailType() string          { return "BadRequest" }
+func (RetryInfo) detailType() string           { return "RetryInfo" }
+func (QuotaFailure) detailType() string        { return "QuotaFailure" }
+func (PreconditionFailure) detailType() string { return "PreconditionFailure" }
+
+// detailTypes creates the details by type when unmarshaling.
+var detailTypes = map[string]func(json.RawMessage) (Detail, error){
+	"BadRequest":          unmarshalDetail[BadRequest],
+	"RetryInfo":           unmarshalDetail[RetryInfo],
+	"QuotaFailure":        unmarshalDetail[QuotaFailure],
+	"PreconditionFailure": unmarshalDetail[PreconditionFailure],
+}
+
+func unmarshalDetail[T Detail](data json.RawMessage) (Detail, error) {
+	var d T
+	err := json.Unmarshal(data, &d)
+	return d, err
+}
+
+// Details returns the details of type T of all errors from this package in the chain, outermost
+// first (or nil if none). Details are kept as values; for pointer types (Details[*BadRequest])
+// pointers to copies are returned.
+//
+//	for _, br := range errors.Details[errors.BadRequest](err) {
+//		...
+//	}
+func Details[T Detail](err error) []T {
+	var found []T
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		e, ok := cur.(*eee)
+		if !ok {
+			continue
+		}
+
+		for _, d := range e.details {
+			if t, ok := asDetail[T](d); ok {
+				found = append(found, t)
+			}
+		}
+	}
+	return found
+}
+
+// asDetail returns the detail as a T; a pointer to a copy of it if T is a pointer to its type.
+func asDetail[T Detail](d Detail) (T, bool) {
+	if t, ok := d.(T); ok {
+		return t, true
+	}
+
+	var zero T
+	pt := reflect.TypeOf(&zero).Elem()
+	if pt.Kind() != reflect.Pointer || pt.Elem() != reflect.TypeOf(d) {
+		return zero, false
+	}
+
+	p := reflect.New(pt.Elem())
+	p.Elem().Set(reflect.ValueOf(d))
+	return p.Interface().(T), true
+}
+
+// AllDetails returns the details of all errors from this package in the chain, outermost first
+// (or nil if none).
+func AllDetails(err error) []Detail {
+	return Details[Detail](err)
+}
@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetails(t *testing.T) {
+	bad := BadRequest{Violations: []FieldViolation{{"age", "must be positive"}}}
+	retry := RetryInfo{Delay: time.Second}
+	root := InvalidArgument(nil, "bad request", "bob", bad)
+	err := Unavailable(fmt.Errorf("wrapped: %w", root), "backend down", retry)
+
+	if got := Details[BadRequest](err); !reflect.DeepEqual(got, []BadRequest{bad}) {
+		t.Errorf("got:%+v want:%+v", got, bad)
+	}
+
+	if got := Details[RetryInfo](err); !reflect.DeepEqual(got, []RetryInfo{retry}) {
+		t.Errorf("got:%+v want:%+v", got, retry)
+	}
+
+	if got := Details[QuotaFailure](err); got != nil {
+		t.Errorf("got:%+v want:nil", got)
+	}
+
+	if got := AllDetails(err); !reflect.DeepEqual(got, []Detail{retry, bad}) {
+		t.Errorf("got:%+v", got)
+	}
+
+	if !reflect.DeepEqual(Args(root), []interface{}{"bob"}) {
+		t.Errorf("details kept as args: %v", Args(root))
+	}
+
+	// ...pointers are kept as values, nil pointers are dropped.
+	ptr := InvalidArgument(nil, "bad request", &bad, (*RetryInfo)(nil))
+	if got := AllDetails(ptr); !reflect.DeepEqual(got, []Detail{bad}) {
+		t.Errorf("got:%+v", got)
+	}
+
+	if got := Details[*BadRequest](err); len(got) != 1 || !reflect.DeepEqual(*got[0], bad) {
+		t.Errorf("got:%+v want:[%+v]", got, &bad)
+	}
+
+	if got := Details[*RetryInfo](root); got != nil {
+		t.Errorf("got:%+v want:nil", got)
+	}
+
+	if out := fmt.Sprintf("%+v", err); !strings.Contains(out, "RetryInfo:{Delay:1s}") || !strings.Contains(out, "BadRequest:{Violations:[{Field:age Description:must be positive}]}") {
+		t.Error(out)
+	}
+}
+
+func TestDetailsMarshal(t *testing.T) {
+	want := []Detail{
+		RetryInfo{Delay: 3 * time.Second},
+		BadRequest{Violations: []FieldViolation{{"age", "must be positive"}, {"name", "required"}}},
+		QuotaFailure{Violations: []QuotaViolation{{"user:bob", "too many requests"}}},
+		PreconditionFailure{Violations: []PreconditionViolation{{"TOS", "user:bob", "terms not accepted"}}},
+	}
+	err := Unavailable(FailedPrecondition(nil, "inner", want[1], want[2], want[3]), "outer", want[0])
+
+	b, jerr := MarshalJSON(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	var fromJSON error
+	if jerr := UnmarshalJSON(b, &fromJSON); jerr != nil {
+		t.Fatal(jerr)
+	}
+
+	b, berr := MarshalBinary(err)
+	if berr != nil {
+		t.Fatal(berr)
+	}
+	var fromBinary error
+	if berr := UnmarshalBinary(b, &fromBinary); berr != nil {
+		t.Fatal(berr)
+	}
+
+	for _, got := range []error{fromJSON, fromBinary} {
+		if !reflect.DeepEqual(AllDetails(got), want) {
+			t.Errorf("got:%+v want:%+v", AllDetails(got), want)
+		}
+	}
+
+	var unknown error
+	if jerr := UnmarshalJSON([]byte(`{"code":3,"desc":"x","details":[{"type":"Nope","value":{}}]}`), &unknown); jerr != nil || AllDetails(unknown) != nil {
+		t.Errorf("unknown detail not dropped; got:%+v err:%v", AllDetails(unknown), jerr)
+	}
+}
@@ -5,16 +5,17 @@
 // - use minimal APIs to minimize the risk of bad/missing args etc
 //
 // Using these opinions as truth:
-// - all root errors always capture the stacktrace of the caller (root error, first error from this package)
+// - all root errors always capture the stacktrace of the caller (root error, first error from this package) unless limited by SetCapture
+// - errors wrapping another error from this package only capture the calling frame; the root has the rest
 // - no fmt.Errorf(...) like formatting APIs are available
 //
 // The codes used by this package are copied from the grpc project. (https://github.com/grpc/grpc-go)
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gopherx/base/errors/codes"
-	"runtime"
 )
 
 type eee struct {
@@ -23,130 +24,152 @@ type eee struct {
 	desc    string
 	args    []interface{}
 	callers []uintptr
+
+	// depth is the Capture used for callers.
+	depth Capture
+
+	// frames holds the symbolized stacktrace of errors unmarshaled from another process.
+	frames []Frame
+
+	// public is the message safe to show to clients; see Public.
+	public string
+
+	// details are the typed payloads of the error; see Details.
+	details []Detail
+
+	// reason is the application reason of the error; see Define.
+	reason string
 }
 
-func callers(skip int) []uintptr {
-	const initial = 64
-	size := initial
-	for {
-		cs := make([]uintptr, size)
-		n := runtime.Callers(skip, cs)
+func newEee(code codes.Code, cause error, desc string, args []interface{}) *eee {
+	args, o := splitArgs(args)
 
-		if n < len(cs) {
-			return cs[:n]
-		}
+	depth := o.depth
+	if !o.hasDepth {
+		depth = depthFor(cause)
+	}
 
-		size = size * 2
+	runHook(code)
+
+	return &eee{
+		code:    code,
+		cause:   cause,
+		desc:    desc,
+		args:    args,
+		callers: callers(4, depth),
+		depth:   depth,
+		public:  o.public,
+		details: o.details,
 	}
 }
 
-func newEee(code codes.Code, cause error, desc string, args []interface{}) *eee {
-	return &eee{code, cause, desc, args, callers(4)}
+// options are set by marker args (Capture, PublicMsg, Detail); markers are not kept as args.
+type options struct {
+	depth    Capture
+	hasDepth bool
+	public   string
+	details  []Detail
 }
 
-// Error implements the error interface.
-func (e *eee) Error() string {
-	return fmt.Sprint(e)
+func isMarker(a interface{}) bool {
+	switch a.(type) {
+	case Capture, PublicMsg, Detail:
+		return true
+	}
+	return false
 }
 
-var (
-	// FormatError formats the error into the buffer. Override to change error output.
-	FormatError = func(s fmt.State,
-		c rune,
-		indent string,
-		err error,
-		code codes.Code,
-		desc string,
-		args []interface{},
-		callers []uintptr) {
-
-		//...is the error from another package?
-		if err != nil {
-			fmt.Fprint(s, indent, "error] ", err)
-			return
+// splitArgs returns the args without marker args and the options set by them.
+func splitArgs(args []interface{}) ([]interface{}, options) {
+	var o options
+	for i, a := range args {
+		if !isMarker(a) {
+			continue
 		}
 
-		fmt.Fprint(s,
-			indent,
-			code.String(), "] ",
-			desc,
-			" args:",
-			args,
-		)
-
-		frames := runtime.CallersFrames(callers)
-		for {
-			frame, ok := frames.Next()
-			if !ok {
-				return
+		// Rare; copy so the caller's slice is left alone.
+		rest := make([]interface{}, 0, len(args)-1)
+		rest = append(rest, args[:i]...)
+		for _, a := range args[i:] {
+			switch t := a.(type) {
+			case Capture:
+				o.depth, o.hasDepth = t, true
+			case PublicMsg:
+				o.public = string(t)
+			case Detail:
+				if d := derefDetail(t); d != nil {
+					o.details = append(o.details, d)
+				}
+			default:
+				rest = append(rest, a)
 			}
-			s.Write(newLine)
-			fmt.Fprint(s, indent, frame.File, ":", frame.Line, " ", frame.Func.Name())
 		}
-	}
-
-	// Array to use for indents of causing errors. Last indent string is used once max is reached.
-	Indents = []string{"", "  ", "    ", "      ", "        "}
-
-	newLine = []byte{'\n'}
-	empty   = []byte{}
-)
-
-// Format implements the fmt.Formatter interface.
-func (e *eee) Format(s fmt.State, c rune) {
-	cur := e
-	cnt := 0
-	indent := ""
-	sep := empty
-
-	updateIndent := func() {
-		if cnt < len(Indents) {
-			indent = Indents[cnt]
+		if len(rest) == 0 {
+			rest = nil
 		}
+		return rest, o
 	}
 
-	for {
-		s.Write(sep)
-		updateIndent()
-
-		FormatError(s, c, indent, nil, cur.code, cur.desc, cur.args, cur.callers)
-		// TODO(d): should only print newline when needed and not always
+	return args, o
+}
 
-		cnt++
+// Error implements the error interface.
+func (e *eee) Error() string {
+	return fmt.Sprint(e)
+}
 
-		nxt, ok := cur.cause.(*eee)
-		if ok {
-			// more eee:s to format
-			cur = nxt
-			sep = newLine
-			continue
-		}
+// Format implements the fmt.Formatter interface; see Formatter.
+func (e *eee) Format(s fmt.State, c rune) {
+	formatterFor(s, c).FormatError(s, c, e)
+}
 
-		if cur.cause == nil {
-			return
-		}
+// Unwrap returns the cause of the error; makes the standard library errors.Is/As/Unwrap walk the chain.
+func (e *eee) Unwrap() error {
+	return e.cause
+}
 
-		// Root cause is not a eee error.
-		s.Write(sep)
-		cnt++
-		updateIndent()
-		FormatError(s, c, indent, cur.cause, codes.OK, "", nil, nil)
-		return
-	}
+// Is reports whether target is an error from this package with the same code. This makes
+// errors.Is(err, ErrNotFound) true for any chain holding a codes.NotFound error.
+func (e *eee) Is(target error) bool {
+	t, ok := target.(*eee)
+	return ok && t.code == e.code
 }
 
-// Code returns the code of the error. Returns codes.Unknown if not an error from this package.
+// Code returns the code of the error. The chain is walked to find the first error from this
+// package so wrapping by other packages (fmt.Errorf("%w")) doesn't lose the code. Errors holding
+// many errors (Multi, errors.Join) resolve the code of their children; see Multi.
+// If no error in the chain is from this package the registered classifiers are asked (see
+// RegisterClassifier); returns codes.Unknown if none of them knows the error.
 func Code(err error) codes.Code {
 	if err == nil {
 		return codes.OK
 	}
 
-	e, ok := err.(*eee)
-	if !ok {
-		return codes.Unknown
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		switch t := cur.(type) {
+		case *eee:
+			return t.code
+		case interface{ Unwrap() []error }:
+			return resolveCode(t.Unwrap())
+		}
 	}
 
-	return e.code
+	return classify(err)
+}
+
+// Codes returns the codes of the errors from this package in the chain, outermost first. Errors
+// holding many errors (Multi, errors.Join) add their resolved code and end the chain.
+func Codes(err error) []codes.Code {
+	var cs []codes.Code
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		switch t := cur.(type) {
+		case *eee:
+			cs = append(cs, t.code)
+		case interface{ Unwrap() []error }:
+			return append(cs, resolveCode(t.Unwrap()))
+		}
+	}
+	return cs
 }
 
 // Cause returns the cause of the error (or nil if not set or an error not created by this package).
@@ -158,9 +181,35 @@ func Cause(err error) error {
 	return e.cause
 }
 
+// Desc returns the description of the first error from this package in the chain (or "" if none).
+func Desc(err error) string {
+	var e *eee
+	if !errors.As(err, &e) {
+		return ""
+	}
+	return e.desc
+}
+
+// Args returns the args of the first error from this package in the chain (or nil if none).
+func Args(err error) []interface{} {
+	var e *eee
+	if !errors.As(err, &e) {
+		return nil
+	}
+	return e.args
+}
+
 // ErrorFunc is a function that creates an error.
 type ErrorFunc func(cause error, desc string, args ...interface{}) error
 
+// ForCode returns the ErrorFunc creating errors with the code. Use when the code is only known at
+// runtime, e.g. when converting errors received from another system.
+func ForCode(code codes.Code) ErrorFunc {
+	return func(cause error, desc string, args ...interface{}) error {
+		return newEee(code, cause, desc, args)
+	}
+}
+
 // Canceled returns a new codes.Canceled error.
 func Canceled(cause error, desc string, args ...interface{}) error {
 	return newEee(codes.Canceled, cause, desc, args)
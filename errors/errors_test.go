@@ -6,6 +6,7 @@ import (
 
 	"fmt"
 	"github.com/gopherx/base/errors/codes"
+	"reflect"
 	"strings"
 )
 
@@ -48,7 +49,7 @@ func TestConstructors(t *testing.T) {
 
 		// Check the error text! Note that the text is not static and will change with
 		// the environment and therefore we can't simply compare against static strings.
-		eparts := strings.Split(err.Error(), "\n")
+		eparts := strings.Split(fmt.Sprintf("%+v", err), "\n")
 		checkDescLine := func(i int, indent string, code codes.Code, desc string) {
 			werr := indent + code.String() + "] " + desc
 			if eparts[i] != werr {
@@ -78,16 +79,16 @@ func TestConstructors(t *testing.T) {
 			t.Error(eparts[i])
 		}
 
-		checkDescLine(0, "", code, desc)
+		checkDescLine(0, "", code, desc+" args:[1 0.5 wrong]")
 		checkStackLine(1, "")
-		checkDescLine(3, "  ", codes.Internal, "waaat")
-		checkStackLine(4, "  ")
+		checkDescLine(2, "  ", codes.Internal, "waaat")
+		checkStackLine(3, "  ")
 	}
 }
 
 func recCallers(n int) []uintptr {
 	if n == 0 {
-		return callers(0)
+		return callers(0, CaptureFull)
 	}
 
 	return recCallers(n - 1)
@@ -109,3 +110,99 @@ func TestCode(t *testing.T) {
 		t.Errorf("wrong code; got:%+v want:%+v", code, codes.OK)
 	}
 }
+
+func TestCodes(t *testing.T) {
+	err := Internal(fmt.Errorf("wrapped: %w", NotFound(errors.New("root"), "no such user")), "lookup failed")
+	if got := Codes(err); !reflect.DeepEqual(got, []codes.Code{codes.Internal, codes.NotFound}) {
+		t.Errorf("got:%v", got)
+	}
+
+	err = Unavailable(Join(NotFound(nil, "a"), Internal(nil, "b")), "batch failed")
+	if got := Codes(err); !reflect.DeepEqual(got, []codes.Code{codes.Unavailable, codes.Internal}) {
+		t.Errorf("got:%v", got)
+	}
+
+	if Codes(errors.New("foreign")) != nil || Codes(nil) != nil {
+		t.Error("codes of foreign errors")
+	}
+}
+
+func TestStdlibChain(t *testing.T) {
+	sentinels := map[codes.Code]error{
+		codes.Canceled:           ErrCanceled,
+		codes.Unknown:            ErrUnknown,
+		codes.InvalidArgument:    ErrInvalidArgument,
+		codes.DeadlineExceeded:   ErrDeadlineExceeded,
+		codes.NotFound:           ErrNotFound,
+		codes.AlreadyExists:      ErrAlreadyExists,
+		codes.PermissionDenied:   ErrPermissionDenied,
+		codes.Unauthenticated:    ErrUnauthenticated,
+		codes.ResourceExhausted:  ErrResourceExhausted,
+		codes.FailedPrecondition: ErrFailedPrecondition,
+		codes.Aborted:            ErrAborted,
+		codes.OutOfRange:         ErrOutOfRange,
+		codes.Unimplemented:      ErrUnimplemented,
+		codes.Internal:           ErrInternal,
+		codes.Unavailable:        ErrUnavailable,
+		codes.DataLoss:           ErrDataLoss,
+	}
+
+	for code, s := range sentinels {
+		if Code(s) != code {
+			t.Error(Code(s), code)
+		}
+	}
+
+	root := errors.New("disk on fire")
+	nf := NotFound(root, "no such user")
+	wrapped := fmt.Errorf("lookup: %w", Internal(fmt.Errorf("db: %w", nf), "query failed"))
+
+	if Code(wrapped) != codes.Internal {
+		t.Errorf("wrong code; got:%+v want:%+v", Code(wrapped), codes.Internal)
+	}
+
+	if !errors.Is(wrapped, ErrNotFound) || !errors.Is(wrapped, ErrInternal) {
+		t.Error("codes in chain not matched", wrapped)
+	}
+
+	if errors.Is(wrapped, ErrDataLoss) {
+		t.Error("matched code not in chain", wrapped)
+	}
+
+	if !errors.Is(wrapped, root) {
+		t.Error("root cause not found", wrapped)
+	}
+
+	if errors.Unwrap(nf) != root {
+		t.Error(errors.Unwrap(nf), root)
+	}
+
+	var e *eee
+	if !errors.As(wrapped, &e) || e.code != codes.Internal {
+		t.Error("As did not find the outermost error", e)
+	}
+}
+
+func TestFields(t *testing.T) {
+	root := DataLoss(nil, "not enough data", Field("read", 3), Field("wanted", 4), "positional")
+	err := Internal(fmt.Errorf("wrapped: %w", root), "decode failed", Field("wanted", 8), Field("file", "a b.bin"))
+
+	got := Fields(err)
+	want := []F{{"wanted", 8}, {"file", "a b.bin"}, {"read", 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got:%+v want:%+v", got, want)
+	}
+
+	if Fields(errors.New("foreign")) != nil {
+		t.Error("foreign error has fields")
+	}
+
+	lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	if lines[0] != `Internal] decode failed wanted=8 file="a b.bin"` {
+		t.Errorf("%q", lines[0])
+	}
+
+	if !strings.Contains(fmt.Sprintf("%+v", err), "DataLoss] not enough data args:[positional] read=3 wanted=4\n") {
+		t.Errorf("%+v", err)
+	}
+}
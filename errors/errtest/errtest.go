@@ -0,0 +1,153 @@
+// Package errtest has test helpers for errors from the errors package: assertions on codes and
+// descriptions, golden files of formatted errors and a deterministic Formatter for snapshots.
+//
+//	err := lookup("bob")
+//	errtest.AssertChain(t, err, codes.Internal, codes.NotFound)
+//	errtest.AssertGolden(t, "testdata/lookup.golden", fmt.Sprintf("%+v", err))
+package errtest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gopherx/base/errors"
+	"github.com/gopherx/base/errors/codes"
+)
+
+var update = flag.Bool("errtest.update", false, "update the golden files of errtest.AssertGolden")
+
+// AssertCode reports an error if the code of err is not want. Returns true if it is.
+func AssertCode(t testing.TB, err error, want codes.Code) bool {
+	t.Helper()
+
+	if got := errors.Code(err); got != want {
+		t.Errorf("wrong code; got:%v want:%v err:%v", got, want, err)
+		return false
+	}
+	return true
+}
+
+// AssertChain reports an error if the codes of the errors from the errors package in the chain,
+// outermost first, are not want; see errors.Codes. Returns true if they are.
+func AssertChain(t testing.TB, err error, want ...codes.Code) bool {
+	t.Helper()
+
+	got := errors.Codes(err)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong chain; got:%v want:%v err:%v", got, want, err)
+		return false
+	}
+	return true
+}
+
+// AssertDescContains reports an error if the description of err doesn't contain substr; see
+// errors.Desc. Returns true if it does.
+func AssertDescContains(t testing.TB, err error, substr string) bool {
+	t.Helper()
+
+	if desc := errors.Desc(err); !strings.Contains(desc, substr) {
+		t.Errorf("wrong desc; got:%q want it to contain:%q err:%v", desc, substr, err)
+		return false
+	}
+	return true
+}
+
+var (
+	// frameLine matches the stacktrace lines of the Verbose and Debug formatters.
+	frameLine = regexp.MustCompile(`(?m)^([ \t]*)\S*?([^/\s]+\.go):\d+ (\S+)$`)
+
+	// frameLines matches the stacktrace lines with their line breaks; the function is captured.
+	frameLines = regexp.MustCompile(`\n[ \t]*\S+\.go:\d+ (\S+)`)
+)
+
+// Module is the package path prefix of the stack frames Normalize keeps, e.g.
+// "github.com/you/app"; frames of other packages (dependencies) are dropped if set. Set it in
+// TestMain before running the tests.
+var Module string
+
+// Normalize makes formatted errors comparable between machines, Go versions and edits: stack
+// frames of the runtime and the testing package are dropped (and of packages outside of Module if
+// set), and the directories and line numbers of the others too ("/src/app/user.go:42 app.lookup"
+// becomes "user.go:_ app.lookup").
+func Normalize(s string) string {
+	s = frameLines.ReplaceAllStringFunc(s, func(line string) string {
+		if fn := frameLines.FindStringSubmatch(line)[1]; !keepFrame(fn) {
+			return ""
+		}
+		return line
+	})
+	return frameLine.ReplaceAllString(s, "${1}${2}:_ ${3}")
+}
+
+// keepFrame returns true if Normalize keeps the frame of the function.
+func keepFrame(fn string) bool {
+	if strings.HasPrefix(fn, "runtime.") || strings.HasPrefix(fn, "testing.") {
+		return false
+	}
+	return len(Module) == 0 || strings.HasPrefix(fn, Module+"/") || strings.HasPrefix(fn, Module+".")
+}
+
+// AssertGolden reports an error if the normalized got (see Normalize) differs from the content of
+// the golden file at path. Run the tests with -errtest.update to write got to the file instead.
+func AssertGolden(t testing.TB, path string, got string) bool {
+	t.Helper()
+
+	got = Normalize(got)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("missing golden file; run with -errtest.update to create it: %v", err)
+		return false
+	}
+
+	if got != string(want) {
+		t.Errorf("%s differs; run with -errtest.update to update it\ngot:\n%s\nwant:\n%s", path, got, want)
+		return false
+	}
+	return true
+}
+
+// Snapshot is a Formatter writing deterministic output for snapshot tests: the Verbose form
+// without stacktraces, for all verbs.
+type Snapshot struct{}
+
+// FormatError implements the errors.Formatter interface.
+func (Snapshot) FormatError(s fmt.State, verb rune, err error) {
+	var b bytes.Buffer
+	errors.Verbose{}.FormatError(&state{&b, s}, 'v', err)
+	s.Write(frameLines.ReplaceAll(b.Bytes(), nil))
+}
+
+// UseSnapshot makes Snapshot the default formatter of all verbs until the test ends; see
+// errors.SetFormatters. Tests using it must not run in parallel with tests formatting errors.
+func UseSnapshot(t testing.TB) {
+	prev := errors.Formatters()
+	errors.SetFormatters(errors.FormatterSet{Short: Snapshot{}, Verbose: Snapshot{}, Debug: Snapshot{}})
+	t.Cleanup(func() { errors.SetFormatters(prev) })
+}
+
+// state is a fmt.State writing to w with the flags, width and precision of s.
+type state struct {
+	w *bytes.Buffer
+	fmt.State
+}
+
+func (s *state) Write(b []byte) (int, error) {
+	return s.w.Write(b)
+}
@@ -0,0 +1,93 @@
+package errtest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gopherx/base/errors"
+	"github.com/gopherx/base/errors/codes"
+)
+
+// recorder records the failures of the assertions.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func lookup(user string) error {
+	return errors.Internal(errors.NotFound(io.EOF, "no such user", errors.Field("user", user)), "lookup failed")
+}
+
+func TestAssertions(t *testing.T) {
+	err := lookup("bob")
+	r := &recorder{TB: t}
+
+	if !AssertCode(r, err, codes.Internal) || !AssertChain(r, err, codes.Internal, codes.NotFound) || !AssertDescContains(r, err, "lookup") {
+		t.Errorf("passing assertions failed: %q", r.failures)
+	}
+
+	if !AssertCode(r, nil, codes.OK) || !AssertChain(r, io.EOF) {
+		t.Errorf("passing assertions failed: %q", r.failures)
+	}
+
+	if AssertCode(r, err, codes.NotFound) || AssertChain(r, err, codes.Internal) || AssertDescContains(r, err, "no such user") {
+		t.Error("failing assertions passed")
+	}
+
+	if len(r.failures) != 3 || !strings.Contains(r.failures[0], "got:Internal want:NotFound") {
+		t.Errorf("got:%q", r.failures)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	in := "Internal] lookup failed\n/home/ci/src/app/user.go:42 example.com/app.lookup\n  NotFound] no such user\n  C:/src/app/db.go:7 example.com/app.(*DB).Get\n  /go/pkg/mod/lib/pq/conn.go:3 github.com/lib/pq.(*conn).query\n  /usr/go/src/testing/testing.go:1690 testing.tRunner\n  /usr/go/src/runtime/asm_amd64.s.go:1 runtime.goexit"
+	want := "Internal] lookup failed\nuser.go:_ example.com/app.lookup\n  NotFound] no such user\n  db.go:_ example.com/app.(*DB).Get\n  conn.go:_ github.com/lib/pq.(*conn).query"
+	if got := Normalize(in); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	Module = "example.com/app"
+	defer func() { Module = "" }()
+
+	want = "Internal] lookup failed\nuser.go:_ example.com/app.lookup\n  NotFound] no such user\n  db.go:_ example.com/app.(*DB).Get"
+	if got := Normalize(in); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	AssertGolden(t, "testdata/lookup.golden", fmt.Sprintf("%+v", lookup("bob")))
+
+	if *update {
+		return
+	}
+
+	r := &recorder{TB: t}
+	if AssertGolden(r, "testdata/lookup.golden", "different") || AssertGolden(r, "testdata/missing.golden", "") {
+		t.Error("golden mismatch passed")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	UseSnapshot(t)
+
+	err := lookup("bob")
+	want := "Internal] lookup failed\n  NotFound] no such user user=bob\n    error] EOF"
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+		if got := fmt.Sprintf(verb, err); got != want {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", verb, got, want)
+		}
+	}
+
+	if got := fmt.Sprintf("%.1v", err); got != "Internal] lookup failed\n  ..." {
+		t.Errorf("got:\n%s", got)
+	}
+}
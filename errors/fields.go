@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// F holds a single key/value field. Pass fields as args to the error constructors; they are
+// rendered as key=value instead of being dumped with the positional args.
+type F struct {
+	Key   string
+	Value interface{}
+}
+
+// Field creates a new F.
+func Field(key string, value interface{}) F {
+	return F{key, value}
+}
+
+// String renders the field as key=value; values with spaces, quotes or '=' are quoted.
+func (f F) String() string {
+	v := fmt.Sprint(f.Value)
+	if len(v) == 0 || strings.ContainsAny(v, " \t\r\n\"=") {
+		v = strconv.Quote(v)
+	}
+	return f.Key + "=" + v
+}
+
+// Fields returns the fields of all errors in the chain, outermost first. A key used by
+// more than one error in the chain is only returned once; the outermost field wins.
+func Fields(err error) []F {
+	var fs []F
+	var seen map[string]bool
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		e, ok := err.(*eee)
+		if !ok {
+			continue
+		}
+
+		for _, a := range e.args {
+			f, ok := a.(F)
+			if !ok || seen[f.Key] {
+				continue
+			}
+
+			if seen == nil {
+				seen = map[string]bool{}
+			}
+			seen[f.Key] = true
+			fs = append(fs, f)
+		}
+	}
+
+	return fs
+}
+
+// formatArgs writes the positional args as args:[...] followed by the fields as key=value.
+// Nothing is written for empty args.
+func formatArgs(w io.Writer, args []interface{}) {
+	var positional []interface{}
+	for _, a := range args {
+		if _, ok := a.(F); !ok {
+			positional = append(positional, a)
+		}
+	}
+
+	if len(positional) > 0 {
+		fmt.Fprint(w, " args:", positional)
+	}
+
+	for _, a := range args {
+		if f, ok := a.(F); ok {
+			io.WriteString(w, " "+f.String())
+		}
+	}
+}
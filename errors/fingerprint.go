@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"strconv"
+)
+
+// Fingerprint returns a stable identity of the error: errors created by the same code path have
+// the same fingerprint regardless of their args. The codes and descriptions of the chain and the
+// stacktrace of the root are hashed; errors from other packages only add their type since their
+// text usually holds args. Returns "" for nil.
+//
+// The stacktrace is hashed as function names and lines, not program counters (which move between
+// runs of position independent binaries), so fingerprints are comparable between processes
+// running the same code and unmarshaled errors keep the fingerprint they had.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	h := fnv.New64a()
+	fingerprint(h, err)
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+func fingerprint(h hash.Hash64, err error) {
+	var root *eee
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		switch t := cur.(type) {
+		case *eee:
+			fmt.Fprintf(h, "%d|%s|", t.code, t.desc)
+			root = t
+
+		case *Multi:
+			for _, child := range t.errs {
+				fingerprint(h, child)
+			}
+			// ...the stacktraces of the children are part of their fingerprints.
+			root = nil
+
+		default:
+			fmt.Fprintf(h, "%T|", cur)
+		}
+	}
+
+	if root == nil {
+		return
+	}
+
+	for _, f := range root.stack() {
+		fmt.Fprintf(h, "%s:%d|", f.Func, f.Line)
+	}
+}
@@ -0,0 +1,50 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+func lookup(id int) error {
+	return Internal(NotFound(nil, "no such user", Field("id", id)), "lookup failed", id)
+}
+
+func TestFingerprint(t *testing.T) {
+	if Fingerprint(nil) != "" {
+		t.Error("nil has a fingerprint")
+	}
+
+	var errs []error
+	for id := 1; id <= 2; id++ {
+		errs = append(errs, lookup(id))
+	}
+	a, b := errs[0], errs[1]
+	if Fingerprint(a) == "" || Fingerprint(a) != Fingerprint(b) {
+		t.Errorf("args changed the fingerprint; %s != %s", Fingerprint(a), Fingerprint(b))
+	}
+
+	others := []error{
+		NotFound(nil, "no such user", Field("id", 1)),
+		Unavailable(NotFound(nil, "no such user"), "lookup failed"),
+		Internal(NotFound(nil, "no such group"), "lookup failed"),
+		fmt.Errorf("wrapped: %w", a),
+	}
+	for _, other := range others {
+		if Fingerprint(other) == Fingerprint(a) {
+			t.Errorf("same fingerprint as %v: %v", a, other)
+		}
+	}
+
+	if Fingerprint(fmt.Errorf("user %d", 1)) != Fingerprint(fmt.Errorf("user %d", 2)) {
+		t.Error("text of foreign errors is part of the fingerprint")
+	}
+
+	b1, _ := MarshalBinary(a)
+	b2, _ := MarshalBinary(b)
+	var ua, ub error
+	UnmarshalBinary(b1, &ua)
+	UnmarshalBinary(b2, &ub)
+	if Fingerprint(ua) != Fingerprint(ub) || Fingerprint(ua) != Fingerprint(a) {
+		t.Error("unmarshaled errors have different fingerprints")
+	}
+}
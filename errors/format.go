@@ -0,0 +1,433 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+// Formatter formats an error and its causes.
+//
+// Errors from this package pick the formatter by fmt verb from the default FormatterSet:
+// %+v uses Verbose, %#v uses Debug and all other verbs (%v, %s, %q) use Short. Libraries should
+// not change the defaults; use Formatted to pick a formatter per call or per logger instead.
+//
+// The built-in formatters use the precision, or the width if no precision is given, as the max
+// number of errors of the chain to format: %.1v only formats the outermost error.
+type Formatter interface {
+	FormatError(s fmt.State, verb rune, err error)
+}
+
+// FormatterSet holds the formatters used for the fmt verbs. Nil formatters use the built-ins.
+type FormatterSet struct {
+	// Short is used for %v and %s; Compact by default.
+	Short Formatter
+
+	// Verbose is used for %+v; Verbose by default (or FormatError if it's overridden).
+	Verbose Formatter
+
+	// Debug is used for %#v; Debug by default.
+	Debug Formatter
+
+	// Indents of causing errors for the built-in formatters without their own; the last one is
+	// used once reached. The package Indents if nil.
+	Indents []string
+}
+
+var (
+	formatters atomic.Pointer[FormatterSet]
+
+	// Indents are the default indents of causing errors for Verbose and Debug. Last indent string
+	// is used once max is reached. Read when formatting, so only change it before errors are
+	// formatted; FormatterSet.Indents is safe to set at any time.
+	Indents = []string{"", "  ", "    ", "      ", "        "}
+
+	newLine = []byte{'\n'}
+)
+
+func init() {
+	formatters.Store(&FormatterSet{})
+}
+
+// SetFormatters sets the default formatters. Meant to be called once by main.
+func SetFormatters(set FormatterSet) {
+	formatters.Store(&set)
+}
+
+// Formatters returns the default formatters.
+func Formatters() FormatterSet {
+	return *formatters.Load()
+}
+
+// formatterFor returns the default formatter for the verb.
+func formatterFor(s fmt.State, verb rune) Formatter {
+	set := formatters.Load()
+	if verb == 'v' && s.Flag('+') {
+		if set.Verbose != nil {
+			return set.Verbose
+		}
+		if f := FormatError; reflect.ValueOf(f).Pointer() != defaultFormatErrorPC {
+			return f
+		}
+		return Verbose{}
+	}
+
+	if verb == 'v' && s.Flag('#') {
+		if set.Debug != nil {
+			return set.Debug
+		}
+		return Debug{}
+	}
+
+	if set.Short != nil {
+		return set.Short
+	}
+	return Compact{}
+}
+
+// LineFormatter formats a single error of a chain: errors from this package get their code,
+// description, args and callers; errors from other packages only err. It's the type of FormatError.
+type LineFormatter func(s fmt.State,
+	c rune,
+	indent string,
+	err error,
+	code codes.Code,
+	desc string,
+	args []interface{},
+	callers []uintptr)
+
+// FormatError implements the Formatter interface; f is called for every error of the chain, on
+// its own line. Errors unmarshaled from another process have no callers.
+func (f LineFormatter) FormatError(s fmt.State, verb rune, err error) {
+	c := chainWriter{w: s, max: depthLimit(s)}
+	for n := 0; err != nil; n++ {
+		if n > 0 {
+			s.Write(newLine)
+		}
+
+		indent := c.indent(n)
+		if c.max >= 0 && n >= c.max {
+			io.WriteString(s, indent+"...")
+			return
+		}
+
+		e, ok := err.(*eee)
+		if !ok {
+			f(s, verb, indent, err, codes.OK, "", nil, nil)
+			return
+		}
+
+		f(s, verb, indent, nil, e.code, e.desc, e.args, e.callers)
+		err = e.cause
+	}
+}
+
+// FormatError formats a single error of a chain for %+v.
+//
+// Deprecated: use SetFormatters with a Verbose formatter (LineFormatter adapts functions like
+// this one). An overridden FormatError is still used for %+v when the default FormatterSet has
+// no Verbose formatter; it must be set before any error is formatted.
+var FormatError LineFormatter = formatErrorLine
+
+var defaultFormatErrorPC = reflect.ValueOf(formatErrorLine).Pointer()
+
+func formatErrorLine(s fmt.State,
+	c rune,
+	indent string,
+	err error,
+	code codes.Code,
+	desc string,
+	args []interface{},
+	callers []uintptr) {
+
+	//...is the error from another package?
+	if err != nil {
+		fmt.Fprint(s, indent, "error] ", err)
+		return
+	}
+
+	fmt.Fprint(s, indent, code.String(), "] ", desc)
+	formatArgs(s, args)
+
+	for _, frame := range symbolize(callers) {
+		s.Write(newLine)
+		fmt.Fprint(s, indent, frame.File, ":", frame.Line, " ", frame.Func)
+	}
+}
+
+// Formatted returns a fmt.Formatter formatting the error with f for all verbs, e.g. for a
+// logger writing logfmt:
+//
+//	log.Print(errors.Formatted(err, errors.Logfmt{}))
+func Formatted(err error, f Formatter) fmt.Formatter {
+	return formatted{err, f}
+}
+
+type formatted struct {
+	err error
+	f   Formatter
+}
+
+func (x formatted) Format(s fmt.State, verb rune) {
+	if x.err == nil {
+		io.WriteString(s, "<nil>")
+		return
+	}
+	x.f.FormatError(s, verb, x.err)
+}
+
+// depthLimit returns the max number of errors of a chain to format; -1 for no limit.
+func depthLimit(s fmt.State) int {
+	if p, ok := s.Precision(); ok {
+		return p
+	}
+
+	if w, ok := s.Width(); ok {
+		return w
+	}
+	return -1
+}
+
+// Compact formats the chain on a single line: "Code] desc: Code] desc: cause". Args and
+// stacktraces are left out. %q quotes the line (%#q backquotes it if possible) and unknown
+// verbs are reported like fmt does: %!d(Code] desc).
+type Compact struct{}
+
+// FormatError implements the Formatter interface.
+func (Compact) FormatError(s fmt.State, verb rune, err error) {
+	max := depthLimit(s)
+
+	switch verb {
+	case 'v', 's':
+		writeCompact(s, err, max)
+
+	case 'q':
+		var b strings.Builder
+		writeCompact(&b, err, max)
+		if s.Flag('#') && strconv.CanBackquote(b.String()) {
+			io.WriteString(s, "`"+b.String()+"`")
+			return
+		}
+		io.WriteString(s, strconv.Quote(b.String()))
+
+	default:
+		fmt.Fprintf(s, "%%!%c(", verb)
+		writeCompact(s, err, max)
+		io.WriteString(s, ")")
+	}
+}
+
+// writeCompact writes at most max errors of the chain; all of them if max < 0. Errors without a
+// description (see WithCode) are followed by their cause without a separator.
+func writeCompact(w io.Writer, err error, max int) {
+	sep := ""
+	for n := 0; err != nil; n++ {
+		io.WriteString(w, sep)
+		sep = ": "
+		if n == max {
+			io.WriteString(w, "...")
+			return
+		}
+
+		switch t := err.(type) {
+		case *eee:
+			io.WriteString(w, t.code.String()+"] "+t.desc)
+			if len(t.desc) == 0 {
+				sep = ""
+			}
+			err = t.cause
+
+		case *Multi:
+			io.WriteString(w, Code(t).String()+"] "+multiDesc+": [")
+			for i, child := range t.errs {
+				if i > 0 {
+					io.WriteString(w, "; ")
+				}
+				writeCompact(w, child, max-n-1)
+			}
+			io.WriteString(w, "]")
+			return
+
+		default:
+			io.WriteString(w, err.Error())
+			return
+		}
+	}
+}
+
+// Verbose formats every error of the chain on its own line with args and stacktrace. Causes
+// are indented. Errors from other packages wrapping errors from this package are followed by
+// the wrapped errors so no stacktrace is lost.
+type Verbose struct {
+	// Indents of causing errors; the Indents of the default FormatterSet (or the package
+	// Indents) if nil.
+	Indents []string
+}
+
+// FormatError implements the Formatter interface.
+func (v Verbose) FormatError(s fmt.State, verb rune, err error) {
+	c := chainWriter{w: s, indents: v.Indents, max: depthLimit(s)}
+	c.chain(err, 0)
+}
+
+// Debug is Verbose with args formatted as Go values, the types of errors from other packages
+// and stacktraces that are not deduplicated.
+type Debug struct {
+	// Indents of causing errors; the Indents of the default FormatterSet (or the package
+	// Indents) if nil.
+	Indents []string
+}
+
+// FormatError implements the Formatter interface.
+func (d Debug) FormatError(s fmt.State, verb rune, err error) {
+	c := chainWriter{w: s, indents: d.Indents, max: depthLimit(s), debug: true}
+	c.chain(err, 0)
+}
+
+// chainWriter writes the multi-line form of Verbose and Debug.
+type chainWriter struct {
+	w       io.Writer
+	indents []string
+	max     int
+	debug   bool
+}
+
+// indent returns the indent of the n:th error in a chain.
+func (c *chainWriter) indent(n int) string {
+	indents := c.indents
+	if indents == nil {
+		indents = formatters.Load().Indents
+	}
+	if indents == nil {
+		indents = Indents
+	}
+
+	if len(indents) == 0 {
+		return ""
+	}
+
+	if n >= len(indents) {
+		return indents[len(indents)-1]
+	}
+	return indents[n]
+}
+
+// chain writes the error and its causes; n is the number of errors written before it.
+func (c *chainWriter) chain(err error, n int) {
+	indent := c.indent(n)
+	if c.max >= 0 && n >= c.max {
+		io.WriteString(c.w, indent+"...")
+		return
+	}
+
+	switch t := err.(type) {
+	case *eee:
+		frames := t.stack()
+		if nxt, ok := t.cause.(*eee); ok && !c.debug {
+			frames = trimCommon(frames, nxt.stack())
+		}
+
+		c.line(indent, t.code.String(), t.desc, t.args, t.details, frames)
+		if t.cause != nil {
+			c.w.Write(newLine)
+			c.chain(t.cause, n+1)
+		}
+
+	case *Multi:
+		c.line(indent, Code(t).String(), multiDesc, []interface{}{Field("count", len(t.errs))}, nil, nil)
+		for _, child := range t.errs {
+			c.w.Write(newLine)
+			c.chain(child, n+1)
+		}
+
+	default:
+		label := "error"
+		if c.debug {
+			label = fmt.Sprintf("error(%T)", err)
+		}
+		io.WriteString(c.w, indent+label+"] "+err.Error())
+
+		// ...keep going if there are errors from this package further down.
+		if nxt := errors.Unwrap(err); nxt != nil && hasOwn(nxt) {
+			c.w.Write(newLine)
+			c.chain(nxt, n+1)
+		}
+	}
+}
+
+func (c *chainWriter) line(indent, code, desc string, args []interface{}, details []Detail, frames []Frame) {
+	io.WriteString(c.w, indent+code+"]")
+	if len(desc) > 0 {
+		io.WriteString(c.w, " "+desc)
+	}
+	if c.debug && len(args) > 0 {
+		fmt.Fprintf(c.w, " args:%#v", args)
+	} else {
+		formatArgs(c.w, args)
+	}
+
+	for _, d := range details {
+		fmt.Fprintf(c.w, " %s:%+v", d.detailType(), d)
+	}
+
+	for _, frame := range frames {
+		c.w.Write(newLine)
+		fmt.Fprint(c.w, indent, frame.File, ":", frame.Line, " ", frame.Func)
+	}
+}
+
+// hasOwn returns true if there is an error from this package in the chain.
+func hasOwn(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		switch err.(type) {
+		case *eee, *Multi:
+			return true
+		}
+	}
+	return false
+}
+
+// JSONLines formats the chain as JSON on a single line; see MarshalJSON.
+type JSONLines struct{}
+
+// FormatError implements the Formatter interface.
+func (JSONLines) FormatError(s fmt.State, verb rune, err error) {
+	b, merr := MarshalJSON(err)
+	if merr != nil {
+		writeCompact(s, merr, -1)
+		return
+	}
+	s.Write(b)
+}
+
+// Logfmt formats the error as logfmt key=value pairs: the code and description of the error,
+// the fields of the chain (see Fields) and the compact form of the cause.
+type Logfmt struct{}
+
+// FormatError implements the Formatter interface.
+func (Logfmt) FormatError(s fmt.State, verb rune, err error) {
+	io.WriteString(s, "code="+Code(err).String())
+
+	e, ok := err.(*eee)
+	if !ok {
+		io.WriteString(s, " "+Field("error", err.Error()).String())
+		return
+	}
+
+	io.WriteString(s, " "+Field("desc", e.desc).String())
+	for _, f := range Fields(err) {
+		io.WriteString(s, " "+f.String())
+	}
+
+	if e.cause != nil {
+		var b strings.Builder
+		writeCompact(&b, e.cause, -1)
+		io.WriteString(s, " "+Field("cause", b.String()).String())
+	}
+}
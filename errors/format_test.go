@@ -0,0 +1,161 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+func TestFormatters(t *testing.T) {
+	root := NotFound(errors.New("disk on fire"), "no such user", Field("id", 7))
+	err := Internal(root, "lookup failed", "positional")
+
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{err.Error(), "Internal] lookup failed: NotFound] no such user: disk on fire"},
+		{fmt.Sprintf("%s", err), "Internal] lookup failed: NotFound] no such user: disk on fire"},
+		{fmt.Sprintf("%v", Join(root, errors.New("foreign"))), "Unknown] multiple errors: [NotFound] no such user: disk on fire; foreign]"},
+		{fmt.Sprint(Formatted(err, Logfmt{})), `code=Internal desc="lookup failed" id=7 cause="NotFound] no such user: disk on fire"`},
+		{fmt.Sprint(Formatted(errors.New("foreign"), Logfmt{})), `code=Unknown error=foreign`},
+		{fmt.Sprint(Formatted(nil, Verbose{})), "<nil>"},
+	}
+
+	for i, c := range tests {
+		if c.got != c.want {
+			t.Errorf("%d:\ngot: %q\nwant:%q", i, c.got, c.want)
+		}
+	}
+}
+
+func TestFormatVerbose(t *testing.T) {
+	err := Internal(NotFound(errors.New("disk on fire"), "no such user", Field("id", 7)), "lookup failed")
+
+	verbose := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	if verbose[0] != "Internal] lookup failed" || !strings.Contains(verbose[1], "format_test.go") {
+		t.Errorf("%+v", err)
+	}
+
+	if last := verbose[len(verbose)-1]; last != "    error] disk on fire" {
+		t.Errorf("%q", last)
+	}
+
+	custom := strings.Split(fmt.Sprint(Formatted(err, Verbose{Indents: []string{"", "> "}})), "\n")
+	if custom[2] != "> NotFound] no such user id=7" {
+		t.Errorf("%q", custom[2])
+	}
+
+	debug := fmt.Sprintf("%#v", err)
+	if !strings.Contains(debug, "args:[]interface {}{errors.F{Key:\"id\", Value:7}}") || !strings.Contains(debug, "error(*errors.errorString)] disk on fire") {
+		t.Error(debug)
+	}
+}
+
+func TestFormatJSONLines(t *testing.T) {
+	err := Internal(NotFound(nil, "no such user"), "lookup failed")
+
+	s := fmt.Sprint(Formatted(err, JSONLines{}))
+	if strings.Contains(s, "\n") {
+		t.Errorf("not a single line: %q", s)
+	}
+
+	var w wire
+	if jerr := json.Unmarshal([]byte(s), &w); jerr != nil || w.Desc != "lookup failed" || w.Cause.Desc != "no such user" {
+		t.Error(jerr, s)
+	}
+}
+
+func TestSetFormatters(t *testing.T) {
+	defer SetFormatters(FormatterSet{})
+
+	err := Internal(nil, "lookup failed")
+	SetFormatters(FormatterSet{Short: Logfmt{}})
+	if err.Error() != `code=Internal desc="lookup failed"` {
+		t.Error(err.Error())
+	}
+
+	if !strings.HasPrefix(fmt.Sprintf("%+v", err), "Internal] lookup failed\n") {
+		t.Errorf("%+v", err)
+	}
+
+	SetFormatters(FormatterSet{Indents: []string{"", "> "}})
+	if got := fmt.Sprintf("%+v", Formatted(Internal(errors.New("EOF"), "lookup failed"), Debug{})); !strings.HasSuffix(got, "\n> error(*errors.errorString)] EOF") {
+		t.Errorf("%q", got)
+	}
+
+	// ...the package Indents are used if the set has none.
+	SetFormatters(FormatterSet{})
+	prev := Indents
+	Indents = []string{"", "| "}
+	defer func() { Indents = prev }()
+	if got := fmt.Sprintf("%+v", Formatted(Internal(errors.New("EOF"), "lookup failed"), Debug{})); !strings.HasSuffix(got, "\n| error(*errors.errorString)] EOF") {
+		t.Errorf("%q", got)
+	}
+}
+
+func TestFormatErrorOverride(t *testing.T) {
+	prev := FormatError
+	defer func() { FormatError = prev }()
+
+	FormatError = func(s fmt.State, c rune, indent string, err error, code codes.Code, desc string, args []interface{}, callers []uintptr) {
+		if err != nil {
+			fmt.Fprint(s, indent, "foreign:", err)
+			return
+		}
+		fmt.Fprint(s, indent, code, ":", desc, ":", len(callers) > 0)
+	}
+
+	err := Internal(NotFound(errors.New("EOF"), "no such user"), "lookup failed")
+	want := "Internal:lookup failed:true\n  NotFound:no such user:true\n    foreign:EOF"
+	if got := fmt.Sprintf("%+v", err); got != want {
+		t.Errorf("got:%q want:%q", got, want)
+	}
+}
+
+func TestFormatVerbs(t *testing.T) {
+	err := Internal(Unavailable(errors.New(`dial "db"`), "backend down"), "lookup failed", Field("id", 7))
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%s", `Internal] lookup failed: Unavailable] backend down: dial "db"`},
+		{"%v", `Internal] lookup failed: Unavailable] backend down: dial "db"`},
+		{"%q", `"Internal] lookup failed: Unavailable] backend down: dial \"db\""`},
+		{"%#q", "`Internal] lookup failed: Unavailable] backend down: dial \"db\"`"},
+		{"%d", `%!d(Internal] lookup failed: Unavailable] backend down: dial "db")`},
+		{"%.1v", `Internal] lookup failed: ...`},
+		{"%2s", `Internal] lookup failed: Unavailable] backend down: ...`},
+		{"%.2q", `"Internal] lookup failed: Unavailable] backend down: ..."`},
+		{"%.3v", `Internal] lookup failed: Unavailable] backend down: dial "db"`},
+		{"%+.1v", "Internal] lookup failed id=7\n*\n  ..."},
+		{"%+.2v", "Internal] lookup failed id=7\n*\n  Unavailable] backend down\n*\n    ..."},
+		{"%+v", "Internal] lookup failed id=7\n*\n  Unavailable] backend down\n*\n    error] dial \"db\""},
+		{"%#.1v", "Internal] lookup failed args:[]interface {}{errors.F{Key:\"id\", Value:7}}\n*\n  ..."},
+	}
+
+	for _, c := range tests {
+		got := fmt.Sprintf(c.format, err)
+
+		// ...collapse stack frames so the test doesn't depend on the environment.
+		var lines []string
+		for _, l := range strings.Split(got, "\n") {
+			if strings.Contains(l, ".go:") {
+				if lines[len(lines)-1] != "*" {
+					lines = append(lines, "*")
+				}
+				continue
+			}
+			lines = append(lines, l)
+		}
+
+		if strings.Join(lines, "\n") != c.want {
+			t.Errorf("%s:\ngot: %q\nwant:%q", c.format, strings.Join(lines, "\n"), c.want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Package grpcstatus converts errors from the errors package to and from gRPC statuses.
+//
+// The codes in the errors/codes package are a copy of the gRPC codes so the conversion is a
+// straight cast. The public message of the error (errors.Public) becomes the status message and
+// the details (errors.Detail) become the matching errdetails messages; descriptions, args and
+// stacktraces never leave the process.
+package grpcstatus
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/gopherx/base/errors"
+	"github.com/gopherx/base/errors/codes"
+)
+
+// Domain is the ErrorInfo domain used for errors converted by this package.
+const Domain = "gopherx.errors"
+
+type grpcStatus interface {
+	GRPCStatus() *status.Status
+}
+
+// ToStatus converts the error to a status. Errors that already are gRPC statuses are returned as is,
+// also when wrapped by other packages (fmt.Errorf("%w")) unless an error from the errors package
+// in the chain classifies them. Returns nil (the OK status) for nil errors.
+func ToStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	var s grpcStatus
+	if stderrors.As(err, &s) && len(errors.Codes(err)) == 0 {
+		return s.GRPCStatus()
+	}
+
+	code := errors.Code(err)
+	st := status.New(grpccodes.Code(code), errors.Public(err))
+
+	reason := errors.Reason(err)
+	if len(reason) == 0 {
+		reason = code.String()
+	}
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: Domain,
+	}}
+	for _, d := range errors.AllDetails(err) {
+		if m := toProto(d); m != nil {
+			details = append(details, m)
+		}
+	}
+
+	withDetails, derr := st.WithDetails(details...)
+	if derr != nil {
+		return st
+	}
+	return withDetails
+}
+
+// FromStatus rebuilds an error with the code and message of the status; the message is both the
+// description and the public message of the error. The reason is kept if it's defined in this
+// process too (see errors.Define). Returns nil for nil and OK statuses.
+func FromStatus(st *status.Status) error {
+	if st.Code() == grpccodes.OK {
+		return nil
+	}
+
+	code := codes.Code(st.Code())
+
+	var reason string
+	args := []interface{}{errors.PublicMsg(st.Message())}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Domain == Domain {
+			reason = info.Reason
+			continue
+		}
+
+		if detail := fromProto(d); detail != nil {
+			args = append(args, detail)
+		}
+	}
+
+	// ...unless the reason is defined with another code here.
+	if c, ok := errors.ReasonCode(reason); ok && c == code {
+		return errors.ForReason(reason)(nil, st.Message(), args...)
+	}
+	return errors.ForCode(code)(nil, st.Message(), args...)
+}
+
+func toProto(d errors.Detail) protoadapt.MessageV1 {
+	switch t := d.(type) {
+	case errors.BadRequest:
+		br := &errdetails.BadRequest{}
+		for _, v := range t.Violations {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: v.Field, Description: v.Description})
+		}
+		return br
+
+	case errors.RetryInfo:
+		return &errdetails.RetryInfo{RetryDelay: durationpb.New(t.Delay)}
+
+	case errors.QuotaFailure:
+		qf := &errdetails.QuotaFailure{}
+		for _, v := range t.Violations {
+			qf.Violations = append(qf.Violations, &errdetails.QuotaFailure_Violation{Subject: v.Subject, Description: v.Description})
+		}
+		return qf
+
+	case errors.PreconditionFailure:
+		pf := &errdetails.PreconditionFailure{}
+		for _, v := range t.Violations {
+			pf.Violations = append(pf.Violations, &errdetails.PreconditionFailure_Violation{Type: v.Type, Subject: v.Subject, Description: v.Description})
+		}
+		return pf
+	}
+	return nil
+}
+
+// fromProto converts a status detail; returns nil for details without an errors.Detail.
+func fromProto(d interface{}) errors.Detail {
+	switch t := d.(type) {
+	case *errdetails.BadRequest:
+		var br errors.BadRequest
+		for _, v := range t.FieldViolations {
+			br.Violations = append(br.Violations, errors.FieldViolation{Field: v.Field, Description: v.Description})
+		}
+		return br
+
+	case *errdetails.RetryInfo:
+		return errors.RetryInfo{Delay: t.RetryDelay.AsDuration()}
+
+	case *errdetails.QuotaFailure:
+		var qf errors.QuotaFailure
+		for _, v := range t.Violations {
+			qf.Violations = append(qf.Violations, errors.QuotaViolation{Subject: v.Subject, Description: v.Description})
+		}
+		return qf
+
+	case *errdetails.PreconditionFailure:
+		var pf errors.PreconditionFailure
+		for _, v := range t.Violations {
+			pf.Violations = append(pf.Violations, errors.PreconditionViolation{Type: v.Type, Subject: v.Subject, Description: v.Description})
+		}
+		return pf
+	}
+	return nil
+}
+
+// fromError converts an error received from a gRPC call.
+func fromError(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return FromStatus(st)
+}
+
+// UnaryServerInterceptor converts errors returned by unary handlers to statuses.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, ToStatus(err).Err()
+	}
+	return resp, nil
+}
+
+// StreamServerInterceptor converts errors returned by stream handlers to statuses.
+func StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := handler(srv, ss)
+	if err != nil {
+		return ToStatus(err).Err()
+	}
+	return nil
+}
+
+// UnaryClientInterceptor converts statuses returned by unary calls to errors.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return fromError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// StreamClientInterceptor converts statuses returned by streaming calls to errors.
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, fromError(err)
+	}
+	return &clientStream{cs}, nil
+}
+
+// clientStream converts the errors of the wrapped stream.
+type clientStream struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	return fromError(s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return fromError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return fromError(s.ClientStream.CloseSend())
+}
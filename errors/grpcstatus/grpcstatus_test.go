@@ -0,0 +1,166 @@
+package grpcstatus
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/gopherx/base/errors"
+	"github.com/gopherx/base/errors/codes"
+)
+
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return nil, errors.NotFound(nil, "no such service", errors.Secret(req.Service), 42, errors.PublicMsg("unknown service"))
+}
+
+func (healthServer) Watch(req *grpc_health_v1.HealthCheckRequest, ss grpc_health_v1.Health_WatchServer) error {
+	return errors.PermissionDenied(nil, "not allowed to watch", req.Service)
+}
+
+func dial(t *testing.T) grpc_health_v1.HealthClient {
+	lis := bufconn.Listen(1 << 16)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor),
+		grpc.StreamInterceptor(StreamServerInterceptor),
+	)
+	grpc_health_v1.RegisterHealthServer(srv, healthServer{})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(StreamClientInterceptor),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestRoundTrip(t *testing.T) {
+	downstream := status.Error(grpccodes.NotFound, "no such row")
+	tests := []struct {
+		err  error
+		code codes.Code
+		desc string
+	}{
+		{nil, codes.OK, ""},
+		{errors.Unavailable(nil, "backend down"), codes.Unavailable, "the service is unavailable"},
+		{errors.Unavailable(nil, "backend down", errors.PublicMsg("try again later")), codes.Unavailable, "try again later"},
+		{errors.Internal(errors.DataLoss(nil, "torn write"), "commit failed", "tx", 7), codes.Internal, "internal error"},
+		{downstream, codes.NotFound, "no such row"},
+		{fmt.Errorf("calling backend: %w", downstream), codes.NotFound, "no such row"},
+		{errors.Internal(downstream, "lookup failed"), codes.Internal, "internal error"},
+	}
+
+	for _, c := range tests {
+		got := FromStatus(ToStatus(c.err))
+		if errors.Code(got) != c.code {
+			t.Errorf("wrong code; got:%+v want:%+v", errors.Code(got), c.code)
+		}
+
+		if errors.Desc(got) != c.desc || (got != nil && errors.Public(got) != c.desc) {
+			t.Errorf("wrong desc; got:%q want:%q", errors.Desc(got), c.desc)
+		}
+
+		if errors.Args(got) != nil {
+			t.Errorf("args leaked; got:%#v", errors.Args(got))
+		}
+	}
+}
+
+var errQuotaStorage = errors.Define("grpcstatus.ResourceExhausted.Storage", codes.ResourceExhausted)
+
+func TestReason(t *testing.T) {
+	got := FromStatus(ToStatus(errQuotaStorage(nil, "disk full")))
+	if errors.Code(got) != codes.ResourceExhausted || errors.Reason(got) != "grpcstatus.ResourceExhausted.Storage" {
+		t.Errorf("reason lost; got:%v reason:%q", got, errors.Reason(got))
+	}
+
+	// ...the code of the status wins if the reason is defined with another code.
+	st := ToStatus(errQuotaStorage(nil, "disk full"))
+	p := st.Proto()
+	p.Code = int32(codes.Unavailable)
+	got = FromStatus(status.FromProto(p))
+	if errors.Code(got) != codes.Unavailable || errors.Reason(got) != "" {
+		t.Errorf("got:%v reason:%q", got, errors.Reason(got))
+	}
+
+	if got := FromStatus(ToStatus(errors.NotFound(nil, "x"))); errors.Reason(got) != "" {
+		t.Errorf("code used as reason; got:%q", errors.Reason(got))
+	}
+}
+
+func TestDetails(t *testing.T) {
+	want := []errors.Detail{
+		errors.RetryInfo{Delay: 3 * time.Second},
+		errors.BadRequest{Violations: []errors.FieldViolation{{Field: "age", Description: "must be positive"}}},
+		errors.QuotaFailure{Violations: []errors.QuotaViolation{{Subject: "user:bob", Description: "too many requests"}}},
+		errors.PreconditionFailure{Violations: []errors.PreconditionViolation{{Type: "TOS", Subject: "user:bob", Description: "terms not accepted"}}},
+	}
+	err := errors.Unavailable(errors.InvalidArgument(nil, "inner", want[1], want[2], want[3]), "outer", want[0])
+
+	got := FromStatus(ToStatus(err))
+	if !reflect.DeepEqual(errors.AllDetails(got), want) {
+		t.Errorf("got:%+v want:%+v", errors.AllDetails(got), want)
+	}
+
+	// ...pointer details are sent too and don't drop the reason.
+	got = FromStatus(ToStatus(errQuotaStorage(nil, "disk full", &errors.QuotaFailure{Violations: want[2].(errors.QuotaFailure).Violations})))
+	if errors.Reason(got) != "grpcstatus.ResourceExhausted.Storage" || !reflect.DeepEqual(errors.AllDetails(got), want[2:3]) {
+		t.Errorf("got:%+v reason:%q", errors.AllDetails(got), errors.Reason(got))
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	client := dial(t)
+	ctx := context.Background()
+
+	_, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "db"})
+	if errors.Code(err) != codes.NotFound {
+		t.Fatalf("wrong code; got:%+v want:%+v err:%v", errors.Code(err), codes.NotFound, err)
+	}
+
+	if errors.Desc(err) != "unknown service" {
+		t.Errorf("wrong desc; got:%q", errors.Desc(err))
+	}
+
+	if text := fmt.Sprintf("%+v", err); strings.Contains(text, "db") || strings.Contains(text, "no such service") {
+		t.Errorf("internal details leaked; got:%s", text)
+	}
+
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{Service: "db"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = stream.Recv()
+	if errors.Code(err) != codes.PermissionDenied {
+		t.Fatalf("wrong code; got:%+v want:%+v err:%v", errors.Code(err), codes.PermissionDenied, err)
+	}
+
+	if errors.Desc(err) != "permission denied" {
+		t.Errorf("wrong desc; got:%q", errors.Desc(err))
+	}
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+// Hook is called for every error created by the constructors with the code of the error and the
+// function creating it, e.g. "github.com/gopherx/base/binary/read.(*BigEndian).Uint32". Panics
+// turned into errors by Recover are reported as codes.Internal of the function that panicked.
+// Must be safe for concurrent use and fast; it runs on the error path of every caller.
+type Hook func(code codes.Code, fn string)
+
+var (
+	hook atomic.Pointer[Hook]
+
+	// creators caches the function names by callers; the set of call sites is bounded by the code.
+	creators sync.Map
+)
+
+// SetHook sets the hook called for every new error (see Hook); nil removes it. Nothing is done
+// for new errors while no hook is set.
+func SetHook(h Hook) {
+	if h == nil {
+		hook.Store(nil)
+		return
+	}
+	hook.Store(&h)
+}
+
+// runHook calls the hook, if set, for an error created by the caller of the constructor.
+func runHook(code codes.Code) {
+	h := hook.Load()
+	if h == nil {
+		return
+	}
+
+	// Skip runtime.Callers, runHook, newEee and the constructor; two slots in case the first
+	// one is an inline marker.
+	var pcs [2]uintptr
+	n := runtime.Callers(4, pcs[:])
+
+	fn, ok := creators.Load(pcs)
+	if !ok {
+		frame, _ := runtime.CallersFrames(pcs[:n]).Next()
+		fn, _ = creators.LoadOrStore(pcs, frame.Function)
+	}
+
+	(*h)(code, fn.(string))
+}
+
+// runPanicHook calls the hook, if set, for a recovered panic with the stacktrace starting at
+// the panic; the creator is the first function outside of the runtime (nil dereferences start
+// in runtime.sigpanic).
+func runPanicHook(frames []Frame) {
+	h := hook.Load()
+	if h == nil {
+		return
+	}
+
+	fn := ""
+	for _, f := range frames {
+		if !strings.HasPrefix(f.Func, "runtime.") {
+			fn = f.Func
+			break
+		}
+	}
+
+	(*h)(codes.Internal, fn)
+}
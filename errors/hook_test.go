@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+func TestHook(t *testing.T) {
+	var mu sync.Mutex
+	var got []string
+	SetHook(func(code codes.Code, fn string) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, code.String()+" "+fn)
+	})
+	defer SetHook(nil)
+
+	NotFound(nil, "missing")
+	ForCode(codes.Aborted)(nil, "conflict")
+	func() { Internal(nil, "broken") }()
+	<-Go(func() error {
+		var m map[string]int
+		m["x"] = 1
+		return nil
+	})
+	<-Go(func() error {
+		var p *int
+		return Internal(nil, "unreachable", *p)
+	})
+
+	want := []string{
+		"NotFound github.com/gopherx/base/errors.TestHook",
+		"Aborted github.com/gopherx/base/errors.TestHook",
+		"Internal github.com/gopherx/base/errors.TestHook.func2",
+		"Internal github.com/gopherx/base/errors.TestHook.func3",
+		"Internal github.com/gopherx/base/errors.TestHook.func4",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got:%q want:%q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got:%q want:%q", got[i], want[i])
+		}
+	}
+
+	SetHook(nil)
+	NotFound(nil, "missing")
+	if len(got) != len(want) {
+		t.Error("hook called after removal")
+	}
+}
@@ -0,0 +1,114 @@
+// Package httperr renders errors from the errors package as RFC 7807 problem details.
+//
+// The status code is picked from the error code (codes.HTTPStatus), the public message of the
+// error (errors.Public) becomes the detail and the reason (errors.Define) is kept. Descriptions,
+// args and stacktraces are never written.
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/golang/glog"
+
+	"github.com/gopherx/base/errors"
+	"github.com/gopherx/base/errors/codes"
+)
+
+// ContentType is the media type of problem details responses.
+const ContentType = "application/problem+json"
+
+// TraceHeader is the response header holding the trace id. If set on the response before
+// WriteError is called the value is copied into the problem.
+var TraceHeader = "X-Trace-Id"
+
+// Problem holds the problem details written for an error.
+type Problem struct {
+	Type    string `json:"type,omitempty"`
+	Title   string `json:"title"`
+	Status  int    `json:"status"`
+	Detail  string `json:"detail,omitempty"`
+	Code    string `json:"code"`
+	Reason  string `json:"reason,omitempty"`
+	TraceID string `json:"traceId,omitempty"`
+}
+
+// NewProblem returns the problem details for the error.
+func NewProblem(err error) *Problem {
+	code := errors.Code(err)
+	status := codes.HTTPStatus(code)
+
+	p := &Problem{
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: errors.Public(err),
+		Code:   code.String(),
+		Reason: errors.Reason(err),
+	}
+
+	return p
+}
+
+// WriteError writes the error as problem details to the response.
+func WriteError(w http.ResponseWriter, err error) {
+	p := NewProblem(err)
+	p.TraceID = w.Header().Get(TraceHeader)
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		glog.Warningf("httperr: failed to write problem; err:%v", err)
+	}
+}
+
+// Recover returns a handler that recovers panics in next (see errors.Recover) and writes them as
+// codes.Internal errors, logged with their stacktrace. Nothing is written if next already sent
+// the headers. http.ErrAbortHandler is not recovered.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+
+		var err error
+		defer func() {
+			if err == nil {
+				return
+			}
+
+			if errors.Cause(err) == http.ErrAbortHandler {
+				panic(http.ErrAbortHandler)
+			}
+
+			err = errors.Annotate(err, errors.Field("method", r.Method), errors.Field("path", r.URL.Path))
+			errors.Log(err, 0)
+			if !rw.wroteHeader {
+				WriteError(w, err)
+			}
+		}()
+		defer errors.Recover(&err)
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// responseWriter records if the headers were sent.
+type responseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,94 @@
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gopherx/base/errors"
+	"github.com/gopherx/base/errors/codes"
+)
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) *Problem {
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Fatalf("wrong content type; got:%q want:%q", ct, ContentType)
+	}
+
+	p := &Problem{}
+	if err := json.Unmarshal(rec.Body.Bytes(), p); err != nil {
+		t.Fatal(err, rec.Body.String())
+	}
+	return p
+}
+
+var errUserNotFound = errors.Define("httperr.NotFound.User", codes.NotFound)
+
+func TestWriteError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set(TraceHeader, "trace-1")
+	WriteError(rec, errUserNotFound(errors.DataLoss(nil, "inner"), "no row in users", errors.Secret("bob"), 7, errors.PublicMsg("no such user")))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("wrong status; got:%d want:%d", rec.Code, http.StatusNotFound)
+	}
+
+	want := &Problem{
+		Title:   "Not Found",
+		Status:  http.StatusNotFound,
+		Detail:  "no such user",
+		Code:    "NotFound",
+		Reason:  "httperr.NotFound.User",
+		TraceID: "trace-1",
+	}
+	if got := decode(t, rec); !reflect.DeepEqual(got, want) {
+		t.Errorf("got:%+v want:%+v", got, want)
+	}
+
+	if body := rec.Body.String(); strings.Contains(body, "bob") || strings.Contains(body, "no row") || strings.Contains(body, "inner") {
+		t.Errorf("internal details leaked; got:%s", body)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/x", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("wrong status; got:%d want:%d", rec.Code, http.StatusInternalServerError)
+	}
+
+	p := decode(t, rec)
+	if p.Code != codes.Internal.String() || p.Detail != "internal error" {
+		t.Errorf("wrong problem; got:%+v", p)
+	}
+
+	// ...nothing is written once the headers are sent.
+	rec = httptest.NewRecorder()
+	Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("partial"))
+		panic("boom")
+	})).ServeHTTP(rec, httptest.NewRequest("GET", "/x", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "partial" {
+		t.Errorf("problem written after the headers; got:%d %q", rec.Code, rec.Body.String())
+	}
+
+	func() {
+		defer func() {
+			if v := recover(); v != http.ErrAbortHandler {
+				t.Errorf("abort not propagated; got:%v", v)
+			}
+		}()
+
+		Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}()
+}
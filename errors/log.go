@@ -0,0 +1,123 @@
+package errors
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/golang/glog"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+// maxLogFrames is the max number of stack frames added to log records.
+const maxLogFrames = 8
+
+// Level returns the log level for errors with the code: broken invariants and lost data are
+// errors, trouble with the environment are warnings and rejected requests are info.
+func Level(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelDebug
+	case codes.Internal, codes.DataLoss, codes.Unknown:
+		return slog.LevelError
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Unimplemented:
+		return slog.LevelWarn
+	}
+	return slog.LevelInfo
+}
+
+// LogValue implements the slog.LogValuer interface. The group holds the code, reason and description,
+// the fields of the chain, the compact form of the cause and the top of the stacktrace of the
+// root error.
+func (e *eee) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("code", e.code.String()),
+		slog.String("desc", e.desc),
+	}
+
+	if len(e.reason) > 0 {
+		attrs = append(attrs, slog.String("reason", e.reason))
+	}
+
+	if fs := Fields(e); len(fs) > 0 {
+		fattrs := make([]any, 0, len(fs))
+		for _, f := range fs {
+			fattrs = append(fattrs, slog.Any(f.Key, f.Value))
+		}
+		attrs = append(attrs, slog.Group("fields", fattrs...))
+	}
+
+	if e.cause != nil {
+		attrs = append(attrs, slog.String("cause", fmt.Sprint(e.cause)))
+	}
+
+	// ...the root has the stacktrace; wrapping errors only the calling frame.
+	root := e
+	for nxt, ok := root.cause.(*eee); ok; nxt, ok = root.cause.(*eee) {
+		root = nxt
+	}
+
+	frames := root.stack()
+	if len(frames) > maxLogFrames {
+		frames = frames[:maxLogFrames]
+	}
+
+	if len(frames) > 0 {
+		stack := make([]string, len(frames))
+		for i, f := range frames {
+			stack[i] = fmt.Sprint(f.File, ":", f.Line, " ", f.Func)
+		}
+		attrs = append(attrs, slog.Any("stack", stack))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements the slog.LogValuer interface.
+func (m *Multi) LogValue() slog.Value {
+	errs := make([]string, len(m.errs))
+	for i, err := range m.errs {
+		errs[i] = fmt.Sprint(err)
+	}
+
+	return slog.GroupValue(
+		slog.String("code", Code(m).String()),
+		slog.String("desc", multiDesc),
+		slog.Any("errors", errs),
+	)
+}
+
+// Log logs the error to glog with the severity picked by Level. Errors are logged with
+// stacktraces (%+v), warnings and info on a single line; info is only logged if v is enabled.
+func Log(err error, v glog.Level) {
+	level, msg, ok := logRecord(err, v)
+	if !ok {
+		return
+	}
+
+	switch level {
+	case slog.LevelError:
+		glog.ErrorDepth(1, msg)
+	case slog.LevelWarn:
+		glog.WarningDepth(1, msg)
+	default:
+		glog.InfoDepth(1, msg)
+	}
+}
+
+// logRecord returns the severity and message Log writes for the error; false if nothing is
+// logged.
+func logRecord(err error, v glog.Level) (slog.Level, string, bool) {
+	if err == nil {
+		return 0, "", false
+	}
+
+	switch level := Level(Code(err)); level {
+	case slog.LevelError:
+		return level, fmt.Sprintf("%+v", err), true
+	case slog.LevelWarn:
+		return level, err.Error(), true
+	default:
+		return slog.LevelInfo, err.Error(), bool(glog.V(v))
+	}
+}
@@ -0,0 +1,101 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/golang/glog"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+func TestLevel(t *testing.T) {
+	tests := map[codes.Code]slog.Level{
+		codes.OK:                 slog.LevelDebug,
+		codes.Internal:           slog.LevelError,
+		codes.DataLoss:           slog.LevelError,
+		codes.Unavailable:        slog.LevelWarn,
+		codes.DeadlineExceeded:   slog.LevelWarn,
+		codes.NotFound:           slog.LevelInfo,
+		codes.InvalidArgument:    slog.LevelInfo,
+		codes.FailedPrecondition: slog.LevelInfo,
+	}
+
+	for code, want := range tests {
+		if Level(code) != want {
+			t.Errorf("%v: got:%v want:%v", code, Level(code), want)
+		}
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	root := NotFound(errors.New("disk on fire"), "no such user", Field("id", 7))
+	err := Internal(root, "lookup failed", Field("table", "users"))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("request failed", "err", err)
+
+	var rec struct {
+		Err struct {
+			Code   string
+			Desc   string
+			Fields map[string]interface{}
+			Cause  string
+			Stack  []string
+		}
+	}
+	if jerr := json.Unmarshal(buf.Bytes(), &rec); jerr != nil {
+		t.Fatal(jerr, buf.String())
+	}
+
+	e := rec.Err
+	if e.Code != "Internal" || e.Desc != "lookup failed" || e.Cause != "NotFound] no such user: disk on fire" {
+		t.Error(buf.String())
+	}
+
+	if e.Fields["id"] != 7.0 || e.Fields["table"] != "users" {
+		t.Error(e.Fields)
+	}
+
+	if len(e.Stack) < 2 || !strings.Contains(e.Stack[0], "errors.TestLogValue") {
+		t.Error("root stack missing", e.Stack)
+	}
+
+	buf.Reset()
+	logger.Info("validation failed", "err", Join(InvalidArgument(nil, "bad age"), OutOfRange(nil, "bad height")))
+	if !strings.Contains(buf.String(), `"err":{"code":"OutOfRange","desc":"multiple errors","errors":["InvalidArgument] bad age","OutOfRange] bad height"]}`) {
+		t.Error(buf.String())
+	}
+}
+
+func TestLog(t *testing.T) {
+	tests := []struct {
+		err   error
+		v     glog.Level
+		level slog.Level
+		msg   string
+		ok    bool
+	}{
+		{nil, 0, 0, "", false},
+		{Internal(nil, "broken"), 0, slog.LevelError, "Internal] broken\n", true},
+		{Unavailable(nil, "flaky"), 0, slog.LevelWarn, "Unavailable] flaky", true},
+		{NotFound(nil, "missing"), 0, slog.LevelInfo, "NotFound] missing", true},
+		{NotFound(nil, "missing"), 9, slog.LevelInfo, "NotFound] missing", false},
+	}
+
+	for i, c := range tests {
+		level, msg, ok := logRecord(c.err, c.v)
+		if level != c.level || !strings.HasPrefix(msg, c.msg) || ok != c.ok {
+			t.Errorf("%d: got:%v,%q,%v want:%v,%q,%v", i, level, msg, ok, c.level, c.msg, c.ok)
+		}
+	}
+
+	if _, msg, _ := logRecord(Unavailable(nil, "flaky"), 0); strings.Contains(msg, "\n") {
+		t.Errorf("warning with stacktrace: %q", msg)
+	}
+}
@@ -0,0 +1,479 @@
+package errors
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+// wire is the serialized form of a single error in a chain. Errors not from this package only
+// keep their text and cause; they are unmarshaled as plain errors. Multi-errors (Multi and
+// errors.Join) keep their children in Errors; a Multi has no text.
+type wire struct {
+	Code    *codes.Code  `json:"code,omitempty"`
+	Desc    string       `json:"desc,omitempty"`
+	Reason  string       `json:"reason,omitempty"`
+	Public  string       `json:"public,omitempty"`
+	Args    []wireArg    `json:"args,omitempty"`
+	Details []wireDetail `json:"details,omitempty"`
+	Frames  []Frame      `json:"frames,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Cause   *wire        `json:"cause,omitempty"`
+	Errors  []*wire      `json:"errors,omitempty"`
+}
+
+// wireArg is a serialized arg. Args are shipped as text; fields keep their key.
+type wireArg struct {
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value"`
+}
+
+// wireDetail is a serialized detail. Details of unknown types are dropped when unmarshaling.
+type wireDetail struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// remote is an error not from this package that was unmarshaled.
+type remote struct {
+	msg   string
+	cause error
+}
+
+func (r *remote) Error() string {
+	return r.msg
+}
+
+func (r *remote) Unwrap() error {
+	return r.cause
+}
+
+// remoteJoin is a multi-error not from this package that was unmarshaled.
+type remoteJoin struct {
+	msg  string
+	errs []error
+}
+
+func (r *remoteJoin) Error() string {
+	return r.msg
+}
+
+func (r *remoteJoin) Unwrap() []error {
+	return r.errs
+}
+
+func toWire(err error) *wire {
+	if err == nil {
+		return nil
+	}
+
+	e, ok := err.(*eee)
+	if !ok {
+		m, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			return &wire{Error: err.Error(), Cause: toWire(errors.Unwrap(err))}
+		}
+
+		w := &wire{}
+		if _, ok := err.(*Multi); !ok {
+			w.Error = err.Error()
+		}
+		for _, child := range m.Unwrap() {
+			if cw := toWire(child); cw != nil {
+				w.Errors = append(w.Errors, cw)
+			}
+		}
+		return w
+	}
+
+	code := e.code
+	w := &wire{Code: &code, Desc: e.desc, Reason: e.reason, Public: e.public, Frames: e.stack(), Cause: toWire(e.cause)}
+	for _, a := range e.args {
+		if f, ok := a.(F); ok {
+			w.Args = append(w.Args, wireArg{f.Key, fmt.Sprint(f.Value)})
+			continue
+		}
+		w.Args = append(w.Args, wireArg{Value: fmt.Sprint(a)})
+	}
+	for _, d := range e.details {
+		v, err := json.Marshal(d)
+		if err != nil {
+			continue
+		}
+		w.Details = append(w.Details, wireDetail{d.detailType(), v})
+	}
+	return w
+}
+
+func fromWire(w *wire) error {
+	if w == nil {
+		return nil
+	}
+
+	if len(w.Errors) > 0 {
+		errs := make([]error, 0, len(w.Errors))
+		for _, cw := range w.Errors {
+			errs = append(errs, fromWire(cw))
+		}
+
+		if len(w.Error) == 0 {
+			return &Multi{errs}
+		}
+		return &remoteJoin{w.Error, errs}
+	}
+
+	cause := fromWire(w.Cause)
+	if w.Code == nil {
+		return &remote{w.Error, cause}
+	}
+
+	var args []interface{}
+	for _, a := range w.Args {
+		if len(a.Key) > 0 {
+			args = append(args, F{a.Key, a.Value})
+			continue
+		}
+		args = append(args, a.Value)
+	}
+
+	var details []Detail
+	for _, wd := range w.Details {
+		unmarshal, ok := detailTypes[wd.Type]
+		if !ok {
+			continue
+		}
+		if d, err := unmarshal(wd.Value); err == nil {
+			details = append(details, d)
+		}
+	}
+
+	return &eee{code: *w.Code, cause: cause, desc: w.Desc, args: args, frames: w.Frames, public: w.Public, details: details, reason: w.Reason}
+}
+
+// MarshalJSON marshals the error and its whole cause chain. Args are marshaled as text and
+// stacktraces as symbolized frames. Errors not from this package only keep their text.
+func MarshalJSON(err error) ([]byte, error) {
+	return json.Marshal(toWire(err))
+}
+
+// UnmarshalJSON unmarshals an error marshaled by MarshalJSON into dest.
+func UnmarshalJSON(data []byte, dest *error) error {
+	var w *wire
+	if err := json.Unmarshal(data, &w); err != nil {
+		return InvalidArgument(err, "malformed error json")
+	}
+
+	*dest = fromWire(w)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface; see MarshalJSON.
+func (e *eee) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(e)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface; see UnmarshalJSON.
+func (e *eee) UnmarshalJSON(data []byte) error {
+	var err error
+	if uerr := UnmarshalJSON(data, &err); uerr != nil {
+		return uerr
+	}
+
+	u, ok := err.(*eee)
+	if !ok {
+		return InvalidArgument(err, "not an error from this package")
+	}
+
+	*e = *u
+	return nil
+}
+
+const binaryVersion = 2
+
+// Kinds of errors in the binary encoding.
+const (
+	kindEnd = iota
+	kindEee
+	kindForeign
+	kindMulti
+)
+
+// Tags of the optional extras of errors in the binary encoding. Unknown tags are skipped so
+// extras can be added without a new version.
+const (
+	tagPublic = 1
+	tagDetail = 2
+	tagReason = 3
+)
+
+// extra is an optional extra of an error in the binary encoding.
+type extra struct {
+	tag uint64
+	v   string
+}
+
+// extras returns the optional extras of the error.
+func (w *wire) extras() []extra {
+	var ex []extra
+	if len(w.Public) > 0 {
+		ex = append(ex, extra{tagPublic, w.Public})
+	}
+	if len(w.Reason) > 0 {
+		ex = append(ex, extra{tagReason, w.Reason})
+	}
+	for _, d := range w.Details {
+		b, _ := json.Marshal(d)
+		ex = append(ex, extra{tagDetail, string(b)})
+	}
+	return ex
+}
+
+// setExtra sets the optional extra of the error with the tag; returns false if v is malformed.
+func (w *wire) setExtra(tag uint64, v string) bool {
+	switch tag {
+	case tagPublic:
+		w.Public = v
+	case tagReason:
+		w.Reason = v
+	case tagDetail:
+		var d wireDetail
+		if err := json.Unmarshal([]byte(v), &d); err != nil {
+			return false
+		}
+		w.Details = append(w.Details, d)
+	}
+	return true
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// MarshalBinary marshals the error and its whole cause chain to a compact binary form.
+// The same information as MarshalJSON is kept.
+func MarshalBinary(err error) ([]byte, error) {
+	return appendChain([]byte{binaryVersion}, toWire(err)), nil
+}
+
+// appendChain appends the chain starting at w followed by kindEnd. The children of
+// multi-errors are nested chains.
+func appendChain(b []byte, w *wire) []byte {
+	for ; w != nil; w = w.Cause {
+		if len(w.Errors) > 0 {
+			b = append(b, kindMulti)
+			b = appendString(b, w.Error)
+			b = binary.AppendUvarint(b, uint64(len(w.Errors)))
+			for _, cw := range w.Errors {
+				b = appendChain(b, cw)
+			}
+			continue
+		}
+
+		if w.Code == nil {
+			b = append(b, kindForeign)
+			b = appendString(b, w.Error)
+			continue
+		}
+
+		b = append(b, kindEee)
+		b = binary.AppendUvarint(b, uint64(*w.Code))
+		b = appendString(b, w.Desc)
+
+		b = binary.AppendUvarint(b, uint64(len(w.Args)))
+		for _, a := range w.Args {
+			b = appendString(b, a.Key)
+			b = appendString(b, a.Value)
+		}
+
+		b = binary.AppendUvarint(b, uint64(len(w.Frames)))
+		for _, f := range w.Frames {
+			b = appendString(b, f.Func)
+			b = appendString(b, f.File)
+			b = binary.AppendVarint(b, int64(f.Line))
+		}
+
+		ex := w.extras()
+		b = binary.AppendUvarint(b, uint64(len(ex)))
+		for _, x := range ex {
+			b = binary.AppendUvarint(b, x.tag)
+			b = appendString(b, x.v)
+		}
+	}
+
+	return append(b, kindEnd)
+}
+
+// UnmarshalBinary unmarshals an error marshaled by MarshalBinary into dest.
+func UnmarshalBinary(data []byte, dest *error) error {
+	d := &decoder{b: data}
+	w := d.chain()
+	if d.err != nil {
+		return d.err
+	}
+
+	*dest = fromWire(w)
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface; see MarshalBinary.
+func (e *eee) MarshalBinary() ([]byte, error) {
+	return MarshalBinary(e)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface; see UnmarshalBinary.
+func (e *eee) UnmarshalBinary(data []byte) error {
+	var err error
+	if uerr := UnmarshalBinary(data, &err); uerr != nil {
+		return uerr
+	}
+
+	u, ok := err.(*eee)
+	if !ok {
+		return InvalidArgument(err, "not an error from this package")
+	}
+
+	*e = *u
+	return nil
+}
+
+// decoder decodes the binary form. Err holds the first error encountered; once an error is
+// found all operations are no-ops.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = DataLoss(nil, "malformed binary error", Field("remaining", len(d.b)))
+	}
+	d.b = nil
+}
+
+func (d *decoder) u8() byte {
+	if len(d.b) == 0 {
+		d.fail()
+		return 0
+	}
+
+	v := d.b[0]
+	d.b = d.b[1:]
+	return v
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+
+	d.b = d.b[n:]
+	return v
+}
+
+// count reads a length; every counted element takes at least one byte so anything larger
+// than the remaining data is malformed.
+func (d *decoder) count() int {
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		d.fail()
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) str() string {
+	n := d.count()
+	s := string(d.b[:n])
+	d.b = d.b[n:]
+	return s
+}
+
+func (d *decoder) chain() *wire {
+	if d.u8() != binaryVersion {
+		d.fail()
+		return nil
+	}
+
+	head := d.list()
+	if len(d.b) != 0 {
+		d.fail()
+	}
+	return head
+}
+
+// list decodes the errors of a chain up to its kindEnd.
+func (d *decoder) list() *wire {
+	var head *wire
+	next := &head
+	for d.err == nil {
+		w := &wire{}
+		switch d.u8() {
+		case kindEnd:
+			return head
+
+		case kindForeign:
+			w.Error = d.str()
+
+		case kindMulti:
+			w.Error = d.str()
+			for i, n := 0, d.count(); i < n && d.err == nil; i++ {
+				w.Errors = append(w.Errors, d.list())
+			}
+
+		case kindEee:
+			v := d.uvarint()
+			if v > math.MaxUint32 {
+				d.fail()
+			}
+			code := codes.Code(v)
+			w.Code = &code
+			w.Desc = d.str()
+
+			for i, n := 0, d.count(); i < n; i++ {
+				key := d.str()
+				value := d.str()
+				w.Args = append(w.Args, wireArg{key, value})
+			}
+
+			for i, n := 0, d.count(); i < n; i++ {
+				fn := d.str()
+				file := d.str()
+				line := d.varint()
+				w.Frames = append(w.Frames, Frame{fn, file, int(line)})
+			}
+
+			for i, n := 0, d.count(); i < n; i++ {
+				tag := d.uvarint()
+				if !w.setExtra(tag, d.str()) {
+					d.fail()
+				}
+			}
+
+		default:
+			d.fail()
+		}
+
+		*next = w
+		next = &w.Cause
+	}
+
+	return nil
+}
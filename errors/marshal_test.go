@@ -0,0 +1,151 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+func TestMarshal(t *testing.T) {
+	root := NotFound(errors.New("disk on fire"), "no such user", Field("id", 7), "x")
+	err := Internal(fmt.Errorf("db: %w", root), "lookup failed")
+
+	tests := map[string]struct {
+		marshal   func(error) ([]byte, error)
+		unmarshal func([]byte, *error) error
+	}{
+		"json":   {MarshalJSON, UnmarshalJSON},
+		"binary": {MarshalBinary, UnmarshalBinary},
+	}
+
+	for name, c := range tests {
+		data, merr := c.marshal(err)
+		if merr != nil {
+			t.Fatal(name, merr)
+		}
+
+		var got error
+		if uerr := c.unmarshal(data, &got); uerr != nil {
+			t.Fatal(name, uerr)
+		}
+
+		if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", err) {
+			t.Errorf("%s: text differs\ngot:\n%+v\nwant:\n%+v", name, got, err)
+		}
+
+		if Code(got) != codes.Internal {
+			t.Errorf("%s: wrong code; got:%v", name, Code(got))
+		}
+
+		if !errors.Is(got, ErrNotFound) {
+			t.Errorf("%s: inner code lost", name)
+		}
+
+		if Cause(got).Error() != Cause(err).Error() {
+			t.Errorf("%s: wrong cause; got:%v", name, Cause(got))
+		}
+
+		if !reflect.DeepEqual(Fields(got), []F{{"id", "7"}}) {
+			t.Errorf("%s: wrong fields; got:%v", name, Fields(got))
+		}
+
+		var nilErr error
+		data, _ = c.marshal(nil)
+		if uerr := c.unmarshal(data, &nilErr); uerr != nil || nilErr != nil {
+			t.Errorf("%s: nil round trip; got:%v err:%v", name, nilErr, uerr)
+		}
+	}
+}
+
+func TestMarshalMulti(t *testing.T) {
+	multi := Join(NotFound(nil, "no such user", Field("id", 7)), errors.New("disk on fire"))
+	err := Internal(errors.Join(multi, InvalidArgument(nil, "bad age")), "validation failed")
+
+	tests := map[string]struct {
+		marshal   func(error) ([]byte, error)
+		unmarshal func([]byte, *error) error
+	}{
+		"json":   {MarshalJSON, UnmarshalJSON},
+		"binary": {MarshalBinary, UnmarshalBinary},
+	}
+
+	for name, c := range tests {
+		data, merr := c.marshal(err)
+		if merr != nil {
+			t.Fatal(name, merr)
+		}
+
+		var got error
+		if uerr := c.unmarshal(data, &got); uerr != nil {
+			t.Fatal(name, uerr)
+		}
+
+		if got.Error() != err.Error() {
+			t.Errorf("%s: text differs\ngot: %v\nwant:%v", name, got, err)
+		}
+
+		if want := Codes(errors.Unwrap(err)); !reflect.DeepEqual(Codes(errors.Unwrap(got)), want) {
+			t.Errorf("%s: wrong codes of the join; got:%v want:%v", name, Codes(errors.Unwrap(got)), want)
+		}
+
+		if !errors.Is(got, ErrNotFound) || !errors.Is(got, ErrInvalidArgument) {
+			t.Errorf("%s: children lost: %v", name, got)
+		}
+
+		var m *Multi
+		if !errors.As(got, &m) || m.Len() != 2 {
+			t.Errorf("%s: Multi not restored: %v", name, got)
+		}
+	}
+}
+
+func TestMarshalMethods(t *testing.T) {
+	err := Unavailable(nil, "backend down")
+
+	data, merr := err.(*eee).MarshalJSON()
+	if merr != nil {
+		t.Fatal(merr)
+	}
+
+	got := &eee{}
+	if uerr := got.UnmarshalJSON(data); uerr != nil {
+		t.Fatal(uerr)
+	}
+
+	if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", err) {
+		t.Errorf("got:\n%+v\nwant:\n%+v", got, err)
+	}
+
+	if uerr := got.UnmarshalJSON([]byte(`{"error":"foreign"}`)); Code(uerr) != codes.InvalidArgument {
+		t.Error(uerr)
+	}
+
+	if !strings.Contains(string(data), `"code":"Unavailable"`) {
+		t.Errorf("code not marshaled by name: %s", data)
+	}
+
+	// ...codes used to be marshaled as numbers.
+	if uerr := got.UnmarshalJSON([]byte(`{"code":14,"desc":"backend down"}`)); uerr != nil || got.code != codes.Unavailable {
+		t.Errorf("numeric code not unmarshaled; got:%v err:%v", got.code, uerr)
+	}
+}
+
+func TestUnmarshalBinaryMalformed(t *testing.T) {
+	data, _ := MarshalBinary(DataLoss(errors.New("root"), "torn", "arg"))
+
+	for i := 0; i < len(data)-1; i++ {
+		var got error
+		if uerr := UnmarshalBinary(data[:i], &got); Code(uerr) != codes.DataLoss {
+			t.Errorf("truncated at %d accepted; got:%v", i, uerr)
+		}
+	}
+
+	var got error
+	if uerr := UnmarshalBinary(append(data, 0), &got); Code(uerr) != codes.DataLoss {
+		t.Errorf("trailing data accepted; got:%v", uerr)
+	}
+}
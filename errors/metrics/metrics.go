@@ -0,0 +1,86 @@
+// Package metrics counts the errors created by the errors package by code and by creating
+// function. The counters are exposed as expvar maps and in the Prometheus text format.
+//
+//	var c metrics.Counters
+//	c.Install()
+//	c.Publish("errors")
+//	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+//		c.WritePrometheus(w)
+//	})
+package metrics
+
+import (
+	"bufio"
+	"expvar"
+	"io"
+	"strings"
+
+	"github.com/gopherx/base/errors"
+	"github.com/gopherx/base/errors/codes"
+)
+
+// Counters counts errors. The zero value is ready to use; the methods are safe for concurrent use.
+type Counters struct {
+	// Codes counts the errors by code name.
+	Codes expvar.Map
+
+	// Funcs counts the errors by creating function, e.g. "github.com/gopherx/base/flag.Parse".
+	Funcs expvar.Map
+}
+
+// Count counts an error; it's the errors.Hook of the counters.
+func (c *Counters) Count(code codes.Code, fn string) {
+	c.Codes.Add(code.String(), 1)
+	c.Funcs.Add(fn, 1)
+}
+
+// Install makes the counters count all new errors; see errors.SetHook.
+func (c *Counters) Install() {
+	errors.SetHook(c.Count)
+}
+
+// Publish publishes the counters as an expvar map with the codes and funcs maps under the name.
+// Panics if the name is already in use, like expvar.Publish.
+func (c *Counters) Publish(name string) {
+	m := new(expvar.Map)
+	m.Set("codes", &c.Codes)
+	m.Set("funcs", &c.Funcs)
+	expvar.Publish(name, m)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// splitFunc splits a function name into its package path and name within the package. Dots in
+// the last element of the path are escaped by the runtime (gopkg.in/yaml%2ev3).
+func splitFunc(fn string) (pkg, name string) {
+	slash := strings.LastIndexByte(fn, '/')
+	dot := strings.IndexByte(fn[slash+1:], '.')
+	if dot < 0 {
+		return "", fn
+	}
+	dot += slash + 1
+	return strings.ReplaceAll(fn[:dot], "%2e", "."), fn[dot+1:]
+}
+
+// WritePrometheus writes the counters in the Prometheus text exposition format:
+//
+//	errors_total{code="NotFound"} 3
+//	errors_by_func_total{package="github.com/gopherx/base/flag",func="Parse"} 3
+func (c *Counters) WritePrometheus(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("# HELP errors_total Errors created by code.\n")
+	bw.WriteString("# TYPE errors_total counter\n")
+	c.Codes.Do(func(kv expvar.KeyValue) {
+		bw.WriteString(`errors_total{code="` + labelEscaper.Replace(kv.Key) + `"} ` + kv.Value.String() + "\n")
+	})
+
+	bw.WriteString("# HELP errors_by_func_total Errors created by creating function.\n")
+	bw.WriteString("# TYPE errors_by_func_total counter\n")
+	c.Funcs.Do(func(kv expvar.KeyValue) {
+		pkg, name := splitFunc(kv.Key)
+		bw.WriteString(`errors_by_func_total{package="` + labelEscaper.Replace(pkg) + `",func="` + labelEscaper.Replace(name) + `"} ` + kv.Value.String() + "\n")
+	})
+
+	return bw.Flush()
+}
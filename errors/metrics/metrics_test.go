@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"bytes"
+	"expvar"
+	"fmt"
+	"testing"
+
+	"github.com/gopherx/base/errors"
+)
+
+func find(id int) error {
+	return errors.NotFound(nil, "no such user", id)
+}
+
+func TestCounters(t *testing.T) {
+	var c Counters
+	c.Install()
+	defer errors.SetHook(nil)
+
+	find(1)
+	find(2)
+	errors.Internal(nil, "broken")
+
+	var buf bytes.Buffer
+	if err := c.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP errors_total Errors created by code.
+# TYPE errors_total counter
+errors_total{code="Internal"} 1
+errors_total{code="NotFound"} 2
+# HELP errors_by_func_total Errors created by creating function.
+# TYPE errors_by_func_total counter
+errors_by_func_total{package="github.com/gopherx/base/errors/metrics",func="TestCounters"} 1
+errors_by_func_total{package="github.com/gopherx/base/errors/metrics",func="find"} 2
+`
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	// ...expvar names can't be published twice; unique per run for -count.
+	name := fmt.Sprintf("errors_test_%p", &c)
+	c.Publish(name)
+	if got := expvar.Get(name).String(); got != `{"codes": {"Internal": 1, "NotFound": 2}, "funcs": {"github.com/gopherx/base/errors/metrics.TestCounters": 1, "github.com/gopherx/base/errors/metrics.find": 2}}` {
+		t.Error(got)
+	}
+}
+
+func TestSplitFunc(t *testing.T) {
+	tests := []struct {
+		fn, pkg, name string
+	}{
+		{"github.com/gopherx/base/binary/read.(*BigEndian).Uint32", "github.com/gopherx/base/binary/read", "(*BigEndian).Uint32"},
+		{"main.main.func1", "main", "main.func1"},
+		{"gopkg.in/yaml%2ev3.Unmarshal", "gopkg.in/yaml.v3", "Unmarshal"},
+		{"", "", ""},
+	}
+
+	for _, tc := range tests {
+		pkg, name := splitFunc(tc.fn)
+		if pkg != tc.pkg || name != tc.name {
+			t.Errorf("%q: got:%q,%q want:%q,%q", tc.fn, pkg, name, tc.pkg, tc.name)
+		}
+	}
+}
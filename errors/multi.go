@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"fmt"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+const multiDesc = "multiple errors"
+
+// precedence orders the codes from most to least severe. The code of a Multi is the code of
+// its most severe child: faults on the server side (lost data, bugs) before faults of the
+// environment (auth, availability, time) before faults of the caller (state, arguments).
+var precedence = []codes.Code{
+	codes.DataLoss,
+	codes.Internal,
+	codes.Unimplemented,
+	codes.Unknown,
+	codes.Unauthenticated,
+	codes.PermissionDenied,
+	codes.Unavailable,
+	codes.DeadlineExceeded,
+	codes.ResourceExhausted,
+	codes.Aborted,
+	codes.Canceled,
+	codes.FailedPrecondition,
+	codes.AlreadyExists,
+	codes.NotFound,
+	codes.OutOfRange,
+	codes.InvalidArgument,
+}
+
+// severity returns the rank of the code in precedence; lower is more severe.
+func severity(code codes.Code) int {
+	for i, c := range precedence {
+		if c == code {
+			return i
+		}
+	}
+
+	// ...codes outside of the known set rank as Unknown.
+	return severity(codes.Unknown)
+}
+
+// resolveCode returns the code of the most severe error; codes.OK if there are none.
+func resolveCode(errs []error) codes.Code {
+	code := codes.OK
+	for _, err := range errs {
+		c := Code(err)
+		if c == codes.OK {
+			continue
+		}
+
+		if code == codes.OK || severity(c) < severity(code) {
+			code = c
+		}
+	}
+	return code
+}
+
+// Multi holds many errors, e.g. all failures found when validating a request. The zero value
+// is ready to use. The code of a Multi is the code of its most severe child (see precedence);
+// errors.Is and errors.As check all children.
+type Multi struct {
+	errs []error
+}
+
+// Join returns a Multi holding the non-nil errors; nil if there are none.
+func Join(errs ...error) error {
+	m := &Multi{}
+	for _, err := range errs {
+		m.Append(err)
+	}
+	return m.Err()
+}
+
+// Append adds the error; nil errors are ignored.
+func (m *Multi) Append(err error) {
+	if err == nil {
+		return
+	}
+	m.errs = append(m.errs, err)
+}
+
+// Len returns the number of errors.
+func (m *Multi) Len() int {
+	return len(m.errs)
+}
+
+// Err returns the Multi as an error; nil if no errors have been added.
+func (m *Multi) Err() error {
+	if len(m.errs) == 0 {
+		return nil
+	}
+	return m
+}
+
+// Unwrap returns the errors; makes the standard library errors.Is/As check all of them.
+func (m *Multi) Unwrap() []error {
+	return m.errs
+}
+
+// Error implements the error interface.
+func (m *Multi) Error() string {
+	return fmt.Sprint(m)
+}
+
+// Format implements the fmt.Formatter interface; see Formatter.
+func (m *Multi) Format(s fmt.State, c rune) {
+	formatterFor(s, c).FormatError(s, c, m)
+}
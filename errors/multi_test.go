@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+func TestMultiCode(t *testing.T) {
+	tests := []struct {
+		errs []error
+		code codes.Code
+	}{
+		{nil, codes.OK},
+		{[]error{nil, nil}, codes.OK},
+		{[]error{InvalidArgument(nil, "a")}, codes.InvalidArgument},
+		{[]error{InvalidArgument(nil, "a"), NotFound(nil, "b")}, codes.NotFound},
+		{[]error{InvalidArgument(nil, "a"), Unavailable(nil, "b"), NotFound(nil, "c")}, codes.Unavailable},
+		{[]error{errors.New("foreign"), InvalidArgument(nil, "b")}, codes.Unknown},
+		{[]error{DataLoss(nil, "a"), Internal(nil, "b")}, codes.DataLoss},
+		{[]error{Join(InvalidArgument(nil, "a"), PermissionDenied(nil, "b")), NotFound(nil, "c")}, codes.PermissionDenied},
+		{[]error{errors.Join(NotFound(nil, "a"), Aborted(nil, "b"))}, codes.Aborted},
+	}
+
+	for i, c := range tests {
+		err := Join(c.errs...)
+		if Code(err) != c.code {
+			t.Errorf("%d: wrong code; got:%v want:%v", i, Code(err), c.code)
+		}
+	}
+
+	if Code(errors.Join(NotFound(nil, "a"), Internal(nil, "b"))) != codes.Internal {
+		t.Error("errors.Join not resolved")
+	}
+
+	if Code(NotFound(Join(Internal(nil, "a")), "b")) != codes.NotFound {
+		t.Error("wrapping error does not own the code")
+	}
+}
+
+func TestMulti(t *testing.T) {
+	var m Multi
+	if m.Err() != nil {
+		t.Fatal("empty Multi is an error")
+	}
+
+	cause := errors.New("not a number")
+	m.Append(InvalidArgument(cause, "bad age", Field("field", "age")))
+	m.Append(nil)
+	m.Append(OutOfRange(nil, "bad height", Field("field", "height")))
+
+	err := Internal(m.Err(), "validate failed")
+	if m.Len() != 2 {
+		t.Fatal(m.Len())
+	}
+
+	if !errors.Is(err, ErrOutOfRange) || !errors.Is(err, ErrInvalidArgument) || !errors.Is(err, cause) {
+		t.Error("children not checked by Is")
+	}
+
+	var mm *Multi
+	if !errors.As(err, &mm) || mm != &m {
+		t.Error("Multi not found by As")
+	}
+
+	lines := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	want := []string{
+		"Internal] validate failed",
+		"  OutOfRange] multiple errors count=2",
+		"    InvalidArgument] bad age field=age",
+		"      error] not a number",
+		"    OutOfRange] bad height field=height",
+	}
+
+	var got []string
+	for _, l := range lines {
+		// ...skip the stack frames.
+		if strings.Contains(l, "] ") {
+			got = append(got, l)
+		}
+	}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
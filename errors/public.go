@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+// PublicMsg is the message of an error that is safe to show to clients. Pass it as one of the
+// args to the constructors; it's not kept as an arg. The description and args are internal.
+type PublicMsg string
+
+const redacted = "[redacted]"
+
+// secret holds a value that must never be shown. The value is kept for debuggers only.
+type secret struct {
+	v interface{}
+}
+
+// Secret marks a value (tokens, paths, user data) that must never be shown; it's formatted as
+// [redacted] by all verbs, loggers and encodings. Use it as an arg or as the value of a field.
+func Secret(v interface{}) interface{} {
+	return secret{v}
+}
+
+// Format implements the fmt.Formatter interface.
+func (secret) Format(s fmt.State, verb rune) {
+	io.WriteString(s, redacted)
+}
+
+// String implements the fmt.Stringer interface.
+func (secret) String() string {
+	return redacted
+}
+
+// LogValue implements the slog.LogValuer interface.
+func (secret) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
+
+// publicMsgs are the messages shown for errors without a PublicMsg.
+var publicMsgs = map[codes.Code]string{
+	codes.OK:                 "ok",
+	codes.Canceled:           "the operation was canceled",
+	codes.Unknown:            "unknown error",
+	codes.InvalidArgument:    "invalid argument",
+	codes.DeadlineExceeded:   "the operation timed out",
+	codes.NotFound:           "not found",
+	codes.AlreadyExists:      "already exists",
+	codes.PermissionDenied:   "permission denied",
+	codes.ResourceExhausted:  "resource exhausted",
+	codes.FailedPrecondition: "the operation is not possible in the current state",
+	codes.Aborted:            "the operation was aborted",
+	codes.OutOfRange:         "out of range",
+	codes.Unimplemented:      "not implemented",
+	codes.Internal:           "internal error",
+	codes.Unavailable:        "the service is unavailable",
+	codes.DataLoss:           "internal error",
+	codes.Unauthenticated:    "not authenticated",
+}
+
+// Public returns the message of the error that is safe to show to clients: the PublicMsg of the
+// outermost error in the chain having one, else a generic message for the code. Only errors with
+// the code of err are searched: a PublicMsg below an error changing the code (e.g. WithCode
+// turning a NotFound into an Internal) describes another failure and isn't used. Descriptions,
+// args and errors from other packages are never part of it. Bridges to other systems (HTTP,
+// gRPC) use it instead of Error.
+func Public(err error) string {
+	code := Code(err)
+
+loop:
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		switch t := cur.(type) {
+		case *eee:
+			if t.code != code {
+				break loop
+			}
+			if len(t.public) > 0 {
+				return t.public
+			}
+
+		case interface{ Unwrap() []error }:
+			if Code(cur) != code {
+				break loop
+			}
+
+			children := t.Unwrap()
+			msgs := make([]string, 0, len(children))
+			for _, child := range children {
+				msgs = append(msgs, Public(child))
+			}
+			return strings.Join(msgs, "; ")
+		}
+	}
+
+	msg, ok := publicMsgs[code]
+	if !ok {
+		return publicMsgs[codes.Unknown]
+	}
+	return msg
+}
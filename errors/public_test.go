@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+func TestPublic(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{NotFound(nil, "no row in users"), "not found"},
+		{NotFound(nil, "no row in users", PublicMsg("no such user")), "no such user"},
+		{Wrap(NotFound(nil, "no row", PublicMsg("no such user")), "lookup failed"), "no such user"},
+		{Internal(NotFound(nil, "no row", PublicMsg("no such user")), "lookup failed"), "internal error"},
+		{WithCode(NotFound(nil, "no row", PublicMsg("no such user")), codes.Internal), "internal error"},
+		{Internal(Join(NotFound(nil, "no row", PublicMsg("no such user"))), "lookup failed"), "internal error"},
+		{Internal(NotFound(nil, "no row", PublicMsg("no such user")), "lookup failed", PublicMsg("try again")), "try again"},
+		{fmt.Errorf("wrapped: %w", NotFound(nil, "no row", PublicMsg("no such user"))), "no such user"},
+		{fmt.Errorf("/etc/passwd: %w", ErrPermissionDenied), "permission denied"},
+		{Join(InvalidArgument(nil, "bad age", PublicMsg("bad age")), OutOfRange(nil, "height 9000")), "bad age; out of range"},
+		{errors.Join(InvalidArgument(nil, "bad age", PublicMsg("bad age")), errors.New("height 9000")), "bad age; unknown error"},
+	}
+
+	for _, tc := range tests {
+		if got := Public(tc.err); got != tc.want {
+			t.Errorf("%v: got:%q want:%q", tc.err, got, tc.want)
+		}
+	}
+
+	if Args(NotFound(nil, "x", PublicMsg("y"))) != nil {
+		t.Error("PublicMsg kept as an arg")
+	}
+}
+
+func TestSecret(t *testing.T) {
+	const token = "hunter2"
+
+	root := NotFound(nil, "no such user", Secret(token), Field("token", Secret(token)))
+	err := Internal(Join(root, InvalidArgument(nil, "bad", Secret(token))), "lookup failed", Field("auth", Secret(token)))
+
+	var outputs []string
+	for _, verb := range []string{"%v", "%s", "%+v", "%#v", "%q"} {
+		outputs = append(outputs, fmt.Sprintf(verb, err))
+	}
+	outputs = append(outputs, err.Error(), Public(err))
+	outputs = append(outputs, fmt.Sprint(Formatted(err, Logfmt{})), fmt.Sprint(Formatted(err, JSONLines{})))
+
+	b, jerr := MarshalJSON(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	outputs = append(outputs, string(b))
+
+	b, berr := MarshalBinary(err)
+	if berr != nil {
+		t.Fatal(berr)
+	}
+	outputs = append(outputs, string(b))
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "err", err)
+	outputs = append(outputs, buf.String())
+
+	for _, out := range outputs {
+		if strings.Contains(out, token) {
+			t.Errorf("secret leaked: %s", out)
+		}
+	}
+
+	if out := fmt.Sprintf("%+v", err); !strings.Contains(out, "auth="+redacted) || !strings.Contains(out, "args:["+redacted+"]") {
+		t.Errorf("secret not shown as %s: %s", redacted, out)
+	}
+}
+
+func TestPublicMarshal(t *testing.T) {
+	err := Wrap(NotFound(nil, "no row", PublicMsg("no such user")), "lookup failed")
+
+	b, jerr := MarshalJSON(err)
+	if jerr != nil {
+		t.Fatal(jerr)
+	}
+	var fromJSON error
+	if jerr := UnmarshalJSON(b, &fromJSON); jerr != nil {
+		t.Fatal(jerr)
+	}
+
+	b, berr := MarshalBinary(err)
+	if berr != nil {
+		t.Fatal(berr)
+	}
+	var fromBinary error
+	if berr := UnmarshalBinary(b, &fromBinary); berr != nil {
+		t.Fatal(berr)
+	}
+
+	for _, got := range []error{fromJSON, fromBinary} {
+		if Public(got) != "no such user" {
+			t.Errorf("public message lost: %q", Public(got))
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+var (
+	reasons   = map[string]codes.Code{}
+	reasonsMu sync.RWMutex
+)
+
+// Define registers an application reason, a finer grained code like "NotFound.User", and returns
+// the ErrorFunc creating errors with it and its canonical code. Call it when initializing
+// packages, like regexp.MustCompile:
+//
+//	var ErrUserNotFound = errors.Define("NotFound.User", codes.NotFound)
+//	...
+//	return ErrUserNotFound(nil, "no such user", id)
+//
+// Panics if the reason is empty or already defined; reasons must be unique across the program.
+func Define(reason string, code codes.Code) ErrorFunc {
+	if len(reason) == 0 {
+		panic("errors: empty reason")
+	}
+
+	reasonsMu.Lock()
+	defer reasonsMu.Unlock()
+
+	if prev, ok := reasons[reason]; ok {
+		panic(fmt.Sprintf("errors: reason %q already defined with code %v", reason, prev))
+	}
+	reasons[reason] = code
+
+	return forReason(reason, code)
+}
+
+func forReason(reason string, code codes.Code) ErrorFunc {
+	return func(cause error, desc string, args ...interface{}) error {
+		e := newEee(code, cause, desc, args)
+		e.reason = reason
+		return e
+	}
+}
+
+// ForReason returns the ErrorFunc of a reason registered with Define, creating the same errors
+// as the func Define returned; nil if the reason isn't defined in this program. Reasons read from
+// the wire (see grpcstatus.FromStatus) must be checked for nil.
+func ForReason(reason string) ErrorFunc {
+	code, ok := ReasonCode(reason)
+	if !ok {
+		return nil
+	}
+	return forReason(reason, code)
+}
+
+// ReasonCode returns the code a reason was registered with by Define; false if not defined.
+func ReasonCode(reason string) (codes.Code, bool) {
+	reasonsMu.RLock()
+	defer reasonsMu.RUnlock()
+
+	code, ok := reasons[reason]
+	return code, ok
+}
+
+// Reason returns the reason of the first error from this package in the chain; the same error
+// Code returns the code of. Returns "" if the error was not created by a Define:d ErrorFunc or
+// if it's held by many errors (Multi, errors.Join): their code is resolved, their reasons are not.
+func Reason(err error) string {
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		switch t := cur.(type) {
+		case *eee:
+			return t.reason
+		case interface{ Unwrap() []error }:
+			return ""
+		}
+	}
+	return ""
+}
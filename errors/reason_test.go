@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+var errUserNotFound = Define("NotFound.User", codes.NotFound)
+
+func TestDefine(t *testing.T) {
+	err := errUserNotFound(nil, "no such user", 7)
+	if Code(err) != codes.NotFound || Reason(err) != "NotFound.User" || Desc(err) != "no such user" {
+		t.Errorf("%+v reason:%q", err, Reason(err))
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Error("not a NotFound error")
+	}
+
+	if Reason(fmt.Errorf("wrapped: %w", err)) != "NotFound.User" {
+		t.Error("reason lost by foreign wrapper")
+	}
+
+	if Reason(Internal(err, "lookup failed")) != "" || Reason(NotFound(nil, "x")) != "" || Reason(nil) != "" {
+		t.Error("reason of another error")
+	}
+
+	// ...multi-errors resolve a code, not a reason.
+	multi := Join(err, Internal(nil, "broken"))
+	if Code(multi) != codes.Internal || Reason(multi) != "" || Reason(Wrap(multi, "lookup failed")) != "" || Reason(errors.Join(err)) != "" {
+		t.Errorf("reason of a multi-error: %q", Reason(multi))
+	}
+
+	if ForReason("NotFound.User") == nil || Reason(ForReason("NotFound.User")(nil, "x")) != "NotFound.User" {
+		t.Error("ForReason")
+	}
+
+	if ForReason("NotFound.Nope") != nil {
+		t.Error("ForReason of undefined reason")
+	}
+
+	if code, ok := ReasonCode("NotFound.User"); !ok || code != codes.NotFound {
+		t.Error("ReasonCode", code, ok)
+	}
+
+	if _, ok := ReasonCode("NotFound.Nope"); ok {
+		t.Error("ReasonCode of undefined reason")
+	}
+
+	for _, reason := range []string{"NotFound.User", ""} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%q: no panic", reason)
+				}
+			}()
+			Define(reason, codes.Internal)
+		}()
+	}
+}
+
+func TestReasonMarshal(t *testing.T) {
+	err := errUserNotFound(nil, "no such user")
+
+	b, _ := MarshalJSON(err)
+	var fromJSON error
+	if jerr := UnmarshalJSON(b, &fromJSON); jerr != nil || Reason(fromJSON) != "NotFound.User" {
+		t.Errorf("json: %q %v", Reason(fromJSON), jerr)
+	}
+
+	b, _ = MarshalBinary(err)
+	var fromBinary error
+	if berr := UnmarshalBinary(b, &fromBinary); berr != nil || Reason(fromBinary) != "NotFound.User" {
+		t.Errorf("binary: %q %v", Reason(fromBinary), berr)
+	}
+}
@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+// Recover turns a panic into a codes.Internal error stored in *errp. Must be deferred directly:
+//
+//	func work() (err error) {
+//		defer errors.Recover(&err)
+//		...
+//	}
+//
+// The stacktrace of the panicking goroutine is captured, starting at the function that panicked,
+// regardless of the Capture policy. A panic value that is an error becomes the cause; other values
+// are kept in the "panic" field.
+func Recover(errp *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	*errp = panicError(v)
+}
+
+func panicError(v interface{}) error {
+	e := &eee{code: codes.Internal, desc: "panic", depth: CaptureFull}
+	if cause, ok := v.(error); ok {
+		e.cause = cause
+	} else {
+		e.args = []interface{}{Field("panic", v)}
+	}
+
+	// Panics are rare; symbolize right away to drop the frames of Recover and the runtime.
+	frames := symbolize(callers(3, CaptureFull))
+	for i, f := range frames {
+		if f.Func == "runtime.gopanic" {
+			frames = frames[i+1:]
+			break
+		}
+	}
+	e.frames = frames
+
+	runPanicHook(frames)
+	return e
+}
+
+// run calls fn and returns its error; panics are returned as errors.
+func run(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
+
+// Go runs fn in a new goroutine; the returned channel receives its error (nil on success).
+// A panic in fn is received as a codes.Internal error instead of crashing the process.
+func Go(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		ch <- run(fn)
+	}()
+	return ch
+}
+
+// Group runs goroutines and returns the first error, like errgroup. Panics in the goroutines are
+// returned as codes.Internal errors. The zero value is ready to use.
+type Group struct {
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+	cancel context.CancelCauseFunc
+}
+
+// NewGroup returns a Group and a context derived from ctx that is canceled when a goroutine of
+// the group fails or Wait returns.
+func NewGroup(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go runs fn in a new goroutine.
+func (g *Group) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		err := run(fn)
+		if err == nil {
+			return
+		}
+
+		g.once.Do(func() {
+			g.err = err
+			if g.cancel != nil {
+				g.cancel(err)
+			}
+		})
+	}()
+}
+
+// Wait waits for all goroutines and returns the first error.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.err
+}
@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+func explode(v interface{}) {
+	panic(v)
+}
+
+func work(v interface{}) (err error) {
+	defer Recover(&err)
+	explode(v)
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	err := work("boom")
+	if Code(err) != codes.Internal {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(Fields(err), []F{{"panic", "boom"}}) {
+		t.Error(Fields(err))
+	}
+
+	frames := err.(*eee).stack()
+	if len(frames) < 2 || frames[0].Func != "github.com/gopherx/base/errors.explode" || frames[1].Func != "github.com/gopherx/base/errors.work" {
+		t.Errorf("stack should start at the panic; got:%+v", err)
+	}
+
+	err = work(io.ErrClosedPipe)
+	if Cause(err) != io.ErrClosedPipe || Fields(err) != nil {
+		t.Errorf("%+v", err)
+	}
+
+	if err := func() (err error) {
+		defer Recover(&err)
+		return nil
+	}(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGo(t *testing.T) {
+	if err := <-Go(func() error { return nil }); err != nil {
+		t.Error(err)
+	}
+
+	if err := <-Go(func() error { explode("boom"); return nil }); Code(err) != codes.Internal {
+		t.Error(err)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	var g Group
+	g.Go(func() error { return nil })
+	if err := g.Wait(); err != nil {
+		t.Error(err)
+	}
+
+	first := NotFound(nil, "first")
+	gc, ctx := NewGroup(context.Background())
+	gc.Go(func() error { return first })
+	gc.Go(func() error {
+		<-ctx.Done()
+		return Canceled(context.Cause(ctx), "second")
+	})
+
+	if err := gc.Wait(); err != first {
+		t.Errorf("first error not returned; got:%v", err)
+	}
+
+	var gp Group
+	gp.Go(func() error { explode(errors.New("boom")); return nil })
+	if err := gp.Wait(); Code(err) != codes.Internal || Cause(err).Error() != "boom" {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,220 @@
+// Package report deduplicates errors before they reach a Sink (logs, an error tracker, ...).
+//
+// Errors are aggregated by fingerprint (see errors.Fingerprint): the first error of a fingerprint
+// is passed on right away and the following ones at most once per Interval, with the number of
+// errors seen in between. A flapping dependency failing thousands of calls results in a handful
+// of reports instead of thousands of identical chains.
+package report
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gopherx/base/errors"
+)
+
+// Entry is the aggregate of the errors with the same fingerprint.
+type Entry struct {
+	Fingerprint string
+
+	// Err is the last error seen.
+	Err error
+
+	// Count is the number of errors seen.
+	Count int64
+
+	// Suppressed is the number of errors seen since the last report that were not reported.
+	Suppressed int64
+
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// Sink receives the reported errors. Calls are serialized by the Reporter.
+type Sink interface {
+	Report(e Entry)
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(e Entry)
+
+// Report implements the Sink interface.
+func (f SinkFunc) Report(e Entry) {
+	f(e)
+}
+
+// Clock is the source of time used by Reporter; replace it in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// DefaultMaxEntries is the number of fingerprints kept if MaxEntries is not set.
+const DefaultMaxEntries = 1024
+
+type entry struct {
+	Entry
+	reported time.Time
+}
+
+// Reporter aggregates errors and reports them to the Sink. Set the fields before the first call
+// to Report; the methods are safe for concurrent use.
+type Reporter struct {
+	// Sink receives the reported errors; errors are only aggregated if nil.
+	Sink Sink
+
+	// Interval is the min time between reports of errors with the same fingerprint; every error
+	// is reported if <= 0.
+	Interval time.Duration
+
+	// MaxEntries caps the number of fingerprints kept; the least recently seen is dropped first.
+	// DefaultMaxEntries if <= 0.
+	MaxEntries int
+
+	// Clock is the source of time; the real clock if nil.
+	Clock Clock
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+func (r *Reporter) now() time.Time {
+	if r.Clock == nil {
+		return realClock{}.Now()
+	}
+	return r.Clock.Now()
+}
+
+// Report records the error and passes it on to the Sink unless an error with the same
+// fingerprint was reported less than Interval ago. Returns true if the error was passed on.
+// Nil errors are ignored.
+func (r *Reporter) Report(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	fp := errors.Fingerprint(err)
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[fp]
+	if !ok {
+		r.evict()
+		e = &entry{Entry: Entry{Fingerprint: fp, FirstSeen: now}}
+		if r.entries == nil {
+			r.entries = map[string]*entry{}
+		}
+		r.entries[fp] = e
+	}
+
+	e.Err = err
+	e.Count++
+	e.LastSeen = now
+
+	if ok && now.Sub(e.reported) < r.Interval {
+		e.Suppressed++
+		return false
+	}
+
+	r.report(e, now)
+	return true
+}
+
+// Flush reports the entries with suppressed errors right away; call before shutting down so the
+// last errors are not lost.
+func (r *Reporter) Flush() {
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.sorted() {
+		if e.Suppressed > 0 {
+			r.report(e, now)
+		}
+	}
+}
+
+// Entries returns the aggregates of all fingerprints kept, most seen first.
+func (r *Reporter) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sorted := r.sorted()
+	entries := make([]Entry, 0, len(sorted))
+	for _, e := range sorted {
+		entries = append(entries, e.Entry)
+	}
+	return entries
+}
+
+// report passes the entry on to the sink; must hold mu.
+func (r *Reporter) report(e *entry, now time.Time) {
+	if r.Sink != nil {
+		r.Sink.Report(e.Entry)
+	}
+	e.reported = now
+	e.Suppressed = 0
+}
+
+// evict drops the least recently seen entry if the reporter is full; must hold mu.
+func (r *Reporter) evict() {
+	max := r.MaxEntries
+	if max <= 0 {
+		max = DefaultMaxEntries
+	}
+	if len(r.entries) < max {
+		return
+	}
+
+	var oldest *entry
+	for _, e := range r.entries {
+		if oldest == nil || e.LastSeen.Before(oldest.LastSeen) {
+			oldest = e
+		}
+	}
+	delete(r.entries, oldest.Fingerprint)
+}
+
+// sorted returns the entries, most seen first; must hold mu.
+func (r *Reporter) sorted() []*entry {
+	sorted := make([]*entry, 0, len(r.entries))
+	for _, e := range r.entries {
+		sorted = append(sorted, e)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Fingerprint < sorted[j].Fingerprint
+	})
+	return sorted
+}
+
+// MemorySink keeps the reported errors in memory; for tests.
+type MemorySink struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Report implements the Sink interface.
+func (s *MemorySink) Report(e Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, e)
+}
+
+// Entries returns the reported entries in the order they were reported.
+func (s *MemorySink) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Entry(nil), s.entries...)
+}
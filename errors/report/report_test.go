@@ -0,0 +1,104 @@
+package report
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gopherx/base/errors"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func flaky(attempt int) error {
+	return errors.Unavailable(nil, "backend down", errors.Field("attempt", attempt))
+}
+
+func TestReporter(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := &fakeClock{start}
+	sink := &MemorySink{}
+	r := &Reporter{Sink: sink, Interval: time.Minute, Clock: clock}
+
+	if r.Report(nil) {
+		t.Error("nil reported")
+	}
+
+	for i := 0; i < 100; i++ {
+		clock.now = start.Add(time.Duration(i) * time.Second)
+		r.Report(flaky(i))
+	}
+	r.Report(errors.NotFound(nil, "no such user"))
+
+	got := sink.Entries()
+	if len(got) != 3 {
+		t.Fatalf("wrong number of reports; got:%d want:3 %+v", len(got), got)
+	}
+
+	if got[0].Count != 1 || got[0].Suppressed != 0 || got[0].Err.Error() != "Unavailable] backend down" {
+		t.Errorf("first report; got:%+v", got[0])
+	}
+
+	// ...the second at 60s after 59 suppressed.
+	if got[1].Count != 61 || got[1].Suppressed != 59 || !got[1].LastSeen.Equal(start.Add(time.Minute)) || !got[1].FirstSeen.Equal(start) {
+		t.Errorf("second report; got:%+v", got[1])
+	}
+
+	if got[2].Count != 1 || got[2].Fingerprint == got[0].Fingerprint {
+		t.Errorf("other error; got:%+v", got[2])
+	}
+
+	r.Flush()
+	got = sink.Entries()
+	if len(got) != 4 || got[3].Count != 100 || got[3].Suppressed != 39 {
+		t.Errorf("flush; got:%+v", got)
+	}
+
+	entries := r.Entries()
+	if len(entries) != 2 || entries[0].Count != 100 || entries[1].Count != 1 {
+		t.Errorf("entries; got:%+v", entries)
+	}
+}
+
+func TestReporterEvict(t *testing.T) {
+	clock := &fakeClock{time.Unix(1000, 0)}
+	r := &Reporter{MaxEntries: 2, Clock: clock}
+
+	for _, desc := range []string{"a", "b", "a", "c"} {
+		clock.now = clock.now.Add(time.Second)
+		r.Report(errors.NotFound(nil, desc))
+	}
+
+	entries := r.Entries()
+	if len(entries) != 2 || errors.Desc(entries[0].Err) != "a" || errors.Desc(entries[1].Err) != "c" {
+		t.Errorf("least recently seen not evicted; got:%+v", entries)
+	}
+}
+
+func TestReporterConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	reports := 0
+	r := &Reporter{Sink: SinkFunc(func(Entry) { mu.Lock(); reports++; mu.Unlock() }), Interval: time.Hour}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.Report(flaky(j))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reports != 1 || r.Entries()[0].Count != 800 {
+		t.Errorf("got:%d reports %+v", reports, r.Entries())
+	}
+}
@@ -0,0 +1,164 @@
+// Package retry retries operations failing with retryable codes (see codes.RetryScope) using
+// exponential backoff with jitter.
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/gopherx/base/errors"
+	"github.com/gopherx/base/errors/codes"
+)
+
+// Clock is the source of time used by Do; replace it in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Policy configures how Do retries. Zero fields but Jitter use the values of DefaultPolicy, so
+// the zero Policy is DefaultPolicy without jitter.
+type Policy struct {
+	// MaxAttempts is the max number of calls; no limit if < 0 (the context decides).
+	MaxAttempts int
+
+	// Initial is the backoff before the second call.
+	Initial time.Duration
+
+	// Max caps the backoff; no cap if < 0.
+	Max time.Duration
+
+	// Multiplier grows the backoff for every retry.
+	Multiplier float64
+
+	// Scope is the widest retry scope of the codes that are retried; codes.RetryCall if
+	// codes.NoRetry. Use codes.RetryTransaction if fn runs a whole transaction so Aborted
+	// errors are retried too.
+	Scope codes.Scope
+
+	// Jitter randomizes the backoff by +/- this fraction of it; [0, 1].
+	Jitter float64
+
+	// Clock is the source of time; the real clock if nil.
+	Clock Clock
+
+	// Rand returns random numbers in [0, 1) for the jitter; math/rand if nil.
+	Rand func() float64
+}
+
+// DefaultPolicy is a reasonable policy for calls to other services.
+var DefaultPolicy = Policy{
+	MaxAttempts: 5,
+	Initial:     100 * time.Millisecond,
+	Max:         10 * time.Second,
+	Multiplier:  2,
+	Jitter:      0.2,
+}
+
+// withDefaults returns the policy with the zero fields set from DefaultPolicy.
+func (p Policy) withDefaults() Policy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = DefaultPolicy.MaxAttempts
+	}
+	if p.Initial <= 0 {
+		p.Initial = DefaultPolicy.Initial
+	}
+	if p.Max == 0 {
+		p.Max = DefaultPolicy.Max
+	}
+	if p.Multiplier <= 0 {
+		p.Multiplier = DefaultPolicy.Multiplier
+	}
+	if p.Scope == codes.NoRetry {
+		p.Scope = codes.RetryCall
+	}
+	return p
+}
+
+// Backoff returns the backoff after the n:th failed call (n >= 1).
+func (p *Policy) Backoff(n int) time.Duration {
+	q := p.withDefaults()
+
+	// ...stop growing at the cap (or before the float overflows to Inf).
+	limit := float64(math.MaxInt64)
+	if q.Max > 0 {
+		limit = float64(q.Max)
+	}
+
+	d := float64(q.Initial)
+	for i := 1; i < n && d < limit; i++ {
+		d *= q.Multiplier
+	}
+
+	if q.Jitter > 0 {
+		rnd := rand.Float64
+		if q.Rand != nil {
+			rnd = q.Rand
+		}
+		d += d * q.Jitter * (2*rnd() - 1)
+	}
+
+	if d >= limit {
+		if q.Max > 0 {
+			return q.Max
+		}
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
+
+// Do calls fn until it succeeds, fails with a code outside of the Scope, the attempts are
+// used up or the context is done. Errors from fn are returned with their code; running out of
+// time is returned as codes.DeadlineExceeded (codes.Canceled if the context was canceled)
+// with the last error from fn as cause. No backoff is started if it would end after the
+// deadline of the context.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	p = p.withDefaults()
+	clock := p.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+
+	for n := 1; ; n++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		code := errors.Code(err)
+		if scope := codes.RetryScope(code); scope == codes.NoRetry || scope > p.Scope {
+			return err
+		}
+
+		if p.MaxAttempts > 0 && n >= p.MaxAttempts {
+			return errors.ForCode(code)(err, "retry attempts exhausted", errors.Field("attempts", n))
+		}
+
+		d := p.Backoff(n)
+		if deadline, ok := ctx.Deadline(); ok && clock.Now().Add(d).After(deadline) {
+			return errors.DeadlineExceeded(err, "retry deadline exceeded", errors.Field("attempts", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.Canceled {
+				return errors.Canceled(err, "retry canceled", errors.Field("attempts", n))
+			}
+			return errors.DeadlineExceeded(err, "retry deadline exceeded", errors.Field("attempts", n))
+
+		case <-clock.After(d):
+		}
+	}
+}
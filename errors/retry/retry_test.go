@@ -0,0 +1,181 @@
+package retry
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gopherx/base/errors"
+	"github.com/gopherx/base/errors/codes"
+)
+
+// fakeClock moves time forward when waited on.
+type fakeClock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func policy(clock Clock) Policy {
+	return Policy{
+		MaxAttempts: 4,
+		Initial:     time.Second,
+		Max:         3 * time.Second,
+		Multiplier:  2,
+		Clock:       clock,
+	}
+}
+
+// failing returns fn failing with the errors in order and then succeeding.
+func failing(calls *int, errs ...error) func(context.Context) error {
+	return func(context.Context) error {
+		*calls++
+		if *calls <= len(errs) {
+			return errs[*calls-1]
+		}
+		return nil
+	}
+}
+
+func TestDo(t *testing.T) {
+	unavailable := errors.Unavailable(nil, "backend down")
+	aborted := errors.Aborted(nil, "tx conflict")
+	notFound := errors.NotFound(nil, "no such row")
+
+	tests := []struct {
+		errs  []error
+		code  codes.Code
+		calls int
+		waits []time.Duration
+	}{
+		{nil, codes.OK, 1, nil},
+		{[]error{unavailable, unavailable}, codes.OK, 3, []time.Duration{time.Second, 2 * time.Second}},
+		{[]error{unavailable, aborted}, codes.Aborted, 2, []time.Duration{time.Second}},
+		{[]error{unavailable, notFound}, codes.NotFound, 2, []time.Duration{time.Second}},
+		{[]error{unavailable, unavailable, unavailable, unavailable}, codes.Unavailable, 4, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}},
+	}
+
+	for i, c := range tests {
+		clock := &fakeClock{now: time.Now()}
+		calls := 0
+		err := Do(context.Background(), policy(clock), failing(&calls, c.errs...))
+
+		if errors.Code(err) != c.code {
+			t.Errorf("%d: wrong code; got:%v want:%v", i, errors.Code(err), c.code)
+		}
+
+		if calls != c.calls {
+			t.Errorf("%d: wrong calls; got:%d want:%d", i, calls, c.calls)
+		}
+
+		if !reflect.DeepEqual(clock.waits, c.waits) {
+			t.Errorf("%d: wrong waits; got:%v want:%v", i, clock.waits, c.waits)
+		}
+	}
+}
+
+func TestDoScope(t *testing.T) {
+	p := policy(&fakeClock{now: time.Now()})
+	p.Scope = codes.RetryTransaction
+
+	calls := 0
+	err := Do(context.Background(), p, failing(&calls, errors.Aborted(nil, "tx conflict"), errors.Unavailable(nil, "backend down")))
+	if err != nil || calls != 3 {
+		t.Error(calls, err)
+	}
+}
+
+func TestDoZeroPolicy(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	calls := 0
+	unavailable := errors.Unavailable(nil, "backend down")
+	err := Do(context.Background(), Policy{Clock: clock}, failing(&calls, unavailable, unavailable, unavailable, unavailable, unavailable, unavailable))
+
+	if errors.Code(err) != codes.Unavailable || calls != DefaultPolicy.MaxAttempts {
+		t.Fatal(calls, err)
+	}
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond}
+	if !reflect.DeepEqual(clock.waits, want) {
+		t.Errorf("wrong waits; got:%v want:%v", clock.waits, want)
+	}
+
+	if d := (&Policy{}).Backoff(20); d != DefaultPolicy.Max {
+		t.Errorf("zero policy not capped; got:%v", d)
+	}
+}
+
+func TestDoDeadline(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	ctx, cancel := context.WithDeadline(context.Background(), clock.now.Add(2500*time.Millisecond))
+	defer cancel()
+
+	calls := 0
+	unavailable := errors.Unavailable(nil, "backend down")
+	err := Do(ctx, policy(clock), failing(&calls, unavailable, unavailable, unavailable))
+
+	if errors.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("wrong code; got:%v err:%v", errors.Code(err), err)
+	}
+
+	if errors.Cause(err) != unavailable || calls != 2 {
+		t.Error(calls, err)
+	}
+}
+
+func TestDoCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := policy(nil)
+	p.Initial = time.Hour
+	p.Max = time.Hour
+
+	calls := 0
+	err := Do(ctx, p, failing(&calls, errors.Unavailable(nil, "backend down")))
+	if errors.Code(err) != codes.Canceled {
+		t.Fatalf("wrong code; got:%v err:%v", errors.Code(err), err)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	p := Policy{Initial: time.Second, Multiplier: 2, Jitter: 0.5}
+
+	p.Rand = func() float64 { return 0 }
+	if d := p.Backoff(2); d != time.Second {
+		t.Error(d)
+	}
+
+	p.Rand = func() float64 { return 0.75 }
+	if d := p.Backoff(3); d != 5*time.Second {
+		t.Error(d)
+	}
+}
+
+func TestBackoffOverflow(t *testing.T) {
+	p := Policy{Initial: time.Second, Max: -1, Multiplier: 2, Jitter: 0.5, Rand: func() float64 { return 0.5 }}
+	for _, n := range []int{40, 80, 2000} {
+		if d := p.Backoff(n); d != math.MaxInt64 {
+			t.Errorf("%d: %v", n, d)
+		}
+	}
+
+	p.Max = time.Minute
+	if d := p.Backoff(80); d != time.Minute {
+		t.Error(d)
+	}
+}
@@ -0,0 +1,30 @@
+package errors
+
+import (
+	"github.com/gopherx/base/errors/codes"
+)
+
+// Sentinel errors, one per code. They carry no cause or stacktrace and are meant to be used
+// as targets for errors.Is; any error from this package matches the sentinel with the same code.
+var (
+	ErrCanceled           error = sentinel(codes.Canceled)
+	ErrUnknown            error = sentinel(codes.Unknown)
+	ErrInvalidArgument    error = sentinel(codes.InvalidArgument)
+	ErrDeadlineExceeded   error = sentinel(codes.DeadlineExceeded)
+	ErrNotFound           error = sentinel(codes.NotFound)
+	ErrAlreadyExists      error = sentinel(codes.AlreadyExists)
+	ErrPermissionDenied   error = sentinel(codes.PermissionDenied)
+	ErrUnauthenticated    error = sentinel(codes.Unauthenticated)
+	ErrResourceExhausted  error = sentinel(codes.ResourceExhausted)
+	ErrFailedPrecondition error = sentinel(codes.FailedPrecondition)
+	ErrAborted            error = sentinel(codes.Aborted)
+	ErrOutOfRange         error = sentinel(codes.OutOfRange)
+	ErrUnimplemented      error = sentinel(codes.Unimplemented)
+	ErrInternal           error = sentinel(codes.Internal)
+	ErrUnavailable        error = sentinel(codes.Unavailable)
+	ErrDataLoss           error = sentinel(codes.DataLoss)
+)
+
+func sentinel(code codes.Code) *eee {
+	return &eee{code: code, desc: code.String()}
+}
@@ -0,0 +1,174 @@
+package errors
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Capture is the number of stack frames captured when an error is created.
+// Use Capture(n) to capture at most n frames.
+type Capture int
+
+const (
+	// CaptureOff captures no stacktrace.
+	CaptureOff Capture = 0
+
+	// CaptureFull captures the full stacktrace; this is the default.
+	CaptureFull Capture = -1
+)
+
+// capture holds the global Capture policy.
+var capture atomic.Int64
+
+func init() {
+	capture.Store(int64(CaptureFull))
+}
+
+// SetCapture sets the global Capture policy for root errors; errors wrapping another error from
+// this package only capture the calling frame. The policy can be overridden per error by passing
+// a Capture as one of the args to the constructor; it's not kept as an arg.
+func SetCapture(c Capture) {
+	capture.Store(int64(c))
+}
+
+// depthFor returns the Capture to use for an error with the cause. Errors wrapping an error
+// from this package (or a Multi) only capture the calling frame; the root error has the stacktrace.
+func depthFor(cause error) Capture {
+	depth := Capture(capture.Load())
+	if depth == CaptureOff {
+		return depth
+	}
+
+	for ; cause != nil; cause = errors.Unwrap(cause) {
+		switch cause.(type) {
+		case *eee, *Multi:
+			return 1
+		}
+	}
+
+	return depth
+}
+
+// Frame is a single symbolized frame of a stacktrace.
+type Frame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// callers returns the callers for a stacktrace of at most depth frames; all of them if depth < 0.
+// One extra caller is captured for limited depths: when skip ends inside inlined frames the
+// runtime stores a marker in the first slot and the frames are resolved from the next one.
+// Trim the symbolized frames with trimDepth.
+func callers(skip int, depth Capture) []uintptr {
+	if depth == CaptureOff {
+		return nil
+	}
+
+	limit := -1
+	if depth > 0 {
+		limit = int(depth) + 1
+	}
+
+	// Fill a buffer on the stack first; only one allocation for the common case.
+	var buf [64]uintptr
+	cs := buf[:]
+	if limit > 0 && limit < len(buf) {
+		cs = buf[:limit]
+	}
+
+	n := runtime.Callers(skip, cs)
+	if n == len(buf) && limit != len(buf) {
+		size := len(buf) * 2
+		for {
+			cs = make([]uintptr, size)
+			n = runtime.Callers(skip, cs)
+
+			if n < len(cs) || (limit > 0 && n >= limit) {
+				break
+			}
+
+			size = size * 2
+		}
+	}
+
+	if limit > 0 && n > limit {
+		n = limit
+	}
+
+	out := make([]uintptr, n)
+	copy(out, cs[:n])
+	return out
+}
+
+// trimDepth returns at most depth frames; all of them if depth < 0.
+func trimDepth(frames []Frame, depth Capture) []Frame {
+	if depth >= 0 && len(frames) > int(depth) {
+		return frames[:depth]
+	}
+	return frames
+}
+
+const maxCachedStacks = 4096
+
+var (
+	// stacks caches symbolized frames per unique set of callers.
+	stacks      sync.Map
+	stacksCount atomic.Int64
+)
+
+func stackKey(callers []uintptr) string {
+	return string(unsafe.Slice((*byte)(unsafe.Pointer(&callers[0])), len(callers)*int(unsafe.Sizeof(callers[0]))))
+}
+
+// symbolize returns the frames of the callers. Symbolized frames are cached; the returned
+// slice must not be modified.
+func symbolize(callers []uintptr) []Frame {
+	if len(callers) == 0 {
+		return nil
+	}
+
+	key := stackKey(callers)
+	if fs, ok := stacks.Load(key); ok {
+		return fs.([]Frame)
+	}
+
+	fs := make([]Frame, 0, len(callers))
+	frames := runtime.CallersFrames(callers)
+	for {
+		frame, ok := frames.Next()
+		if !ok {
+			break
+		}
+		fs = append(fs, Frame{frame.Function, frame.File, frame.Line})
+	}
+
+	if stacksCount.Load() < maxCachedStacks {
+		if _, loaded := stacks.LoadOrStore(key, fs); !loaded {
+			stacksCount.Add(1)
+		}
+	}
+
+	return fs
+}
+
+// trimCommon returns outer without the frames it has in common with the end of inner, the
+// stacktrace of its cause; those are printed with the cause. At least one frame is kept.
+func trimCommon(outer, inner []Frame) []Frame {
+	n := len(outer)
+	for i := len(inner) - 1; n > 1 && i >= 0 && outer[n-1] == inner[i]; i-- {
+		n--
+	}
+	return outer[:n]
+}
+
+// stack returns the symbolized stacktrace of the error.
+func (e *eee) stack() []Frame {
+	if e.frames != nil {
+		return e.frames
+	}
+	return trimDepth(symbolize(e.callers), e.depth)
+}
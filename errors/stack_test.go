@@ -0,0 +1,115 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	defer SetCapture(CaptureFull)
+
+	full := recCallers(100)
+	if len(full) < 100 {
+		t.Fatal(len(full))
+	}
+
+	if cs := callers(0, CaptureOff); cs != nil {
+		t.Error(cs)
+	}
+
+	if fs := trimDepth(symbolize(callers(0, Capture(3))), 3); len(fs) != 3 {
+		t.Error(fs)
+	}
+
+	SetCapture(CaptureOff)
+	if e := NotFound(nil, "nope").(*eee); e.callers != nil {
+		t.Error("global policy ignored", e.callers)
+	}
+
+	args := []interface{}{"a", Capture(2), "b"}
+	e := NotFound(nil, "nope", args...).(*eee)
+	if len(e.stack()) != 2 {
+		t.Error("per call policy ignored", e.stack())
+	}
+
+	if fmt.Sprint(e.args) != "[a b]" || fmt.Sprint(args) != "[a 2 b]" {
+		t.Error(e.args, args)
+	}
+}
+
+func TestSymbolizeCached(t *testing.T) {
+	e := Internal(nil, "cached").(*eee)
+
+	fs0 := e.stack()
+	fs1 := e.stack()
+	if len(fs0) == 0 || &fs0[0] != &fs1[0] {
+		t.Error("frames not cached")
+	}
+
+	if fs0[0].Func != "github.com/gopherx/base/errors.TestSymbolizeCached" {
+		t.Error(fs0[0])
+	}
+}
+
+func benchmarkNew(b *testing.B, c Capture) {
+	defer SetCapture(CaptureFull)
+	SetCapture(c)
+
+	cause := errors.New("short read")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = DataLoss(cause, "not enough data")
+	}
+}
+
+func BenchmarkNewCaptureOff(b *testing.B) {
+	benchmarkNew(b, CaptureOff)
+}
+
+func BenchmarkNewCapture8(b *testing.B) {
+	benchmarkNew(b, Capture(8))
+}
+
+func BenchmarkNewCaptureFull(b *testing.B) {
+	benchmarkNew(b, CaptureFull)
+}
+
+func BenchmarkFormat(b *testing.B) {
+	err := Internal(DataLoss(nil, "not enough data", Field("read", 3)), "decode failed")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fmt.Fprint(io.Discard, err)
+	}
+}
+
+func TestWrapCapture(t *testing.T) {
+	root := NotFound(nil, "root")
+	wrap := Internal(fmt.Errorf("db: %w", root), "wrapped")
+	full := Internal(root, "wrapped", CaptureFull)
+
+	if len(root.(*eee).callers) < 2 {
+		t.Error("root should capture the stacktrace", root.(*eee).callers)
+	}
+
+	if fs := wrap.(*eee).stack(); len(fs) != 1 || fs[0].Func != "github.com/gopherx/base/errors.TestWrapCapture" {
+		t.Error("wrapper should capture one frame", fs)
+	}
+
+	if len(Internal(errors.New("foreign"), "root").(*eee).callers) < 2 {
+		t.Error("error wrapping foreign errors is a root")
+	}
+
+	// ...frames in common with the root are only printed once.
+	lines := strings.Split(fmt.Sprintf("%+v", full), "\n")
+	if len(lines) != 2+len(root.(*eee).stack())+1 {
+		t.Errorf("trace not deduplicated:\n%+v", full)
+	}
+
+	if !strings.HasPrefix(lines[2], "  NotFound] root") {
+		t.Errorf("%q", lines[2])
+	}
+}
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"github.com/gopherx/base/errors/codes"
+)
+
+// Wrap returns a new error with the code (and reason) of the cause; use in middle layers adding
+// context without changing the classification of the root. Returns nil if the cause is nil.
+//
+//	if err := store.Get(id); err != nil {
+//		return errors.Wrap(err, "loading profile", errors.Field("id", id))
+//	}
+func Wrap(cause error, desc string, args ...interface{}) error {
+	if cause == nil {
+		return nil
+	}
+
+	e := newEee(Code(cause), cause, desc, args)
+	e.reason = Reason(cause)
+	return e
+}
+
+// WithCode returns a new error with the code and no description wrapping the cause; use to
+// re-classify an error, e.g. a NotFound from a dependency that is an Internal error for the
+// caller. Returns nil if the cause is nil.
+func WithCode(cause error, code codes.Code) error {
+	if cause == nil {
+		return nil
+	}
+
+	return newEee(code, cause, "", nil)
+}
+
+// Annotate adds the args to the error without creating a new error in the chain or capturing a
+// stacktrace. Errors from this package are copied with the args appended (the error passed in is
+// left alone); marker args (PublicMsg, Detail) apply to the copy. Other errors are wrapped by an
+// error with their code, no description and no stacktrace. Annotations are not new errors: the
+// Hook isn't called. Returns nil if the error is nil.
+func Annotate(err error, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	args, o := splitArgs(args)
+
+	e, ok := err.(*eee)
+	if !ok {
+		return &eee{code: Code(err), cause: err, args: args, depth: CaptureOff, public: o.public, details: o.details}
+	}
+
+	cp := *e
+	cp.args = append(e.args[:len(e.args):len(e.args)], args...)
+	cp.details = append(e.details[:len(e.details):len(e.details)], o.details...)
+	if len(o.public) > 0 {
+		cp.public = o.public
+	}
+	return &cp
+}
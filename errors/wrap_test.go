@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/gopherx/base/errors/codes"
+)
+
+func TestWrap(t *testing.T) {
+	if Wrap(nil, "x") != nil || WithCode(nil, codes.Internal) != nil || Annotate(nil, "x") != nil {
+		t.Error("nil not kept")
+	}
+
+	root := errUserNotFound(nil, "no such user", PublicMsg("no such user"))
+	err := Wrap(root, "loading profile", Field("id", 7))
+	if Code(err) != codes.NotFound || Reason(err) != "NotFound.User" || Public(err) != "no such user" {
+		t.Errorf("classification lost; got:%v reason:%q", err, Reason(err))
+	}
+
+	if got := fmt.Sprint(err); got != "NotFound] loading profile: NotFound] no such user" {
+		t.Error(got)
+	}
+
+	if len(err.(*eee).stack()) != 1 {
+		t.Errorf("wrapper captured a stacktrace; got:%+v", err)
+	}
+
+	if Code(Wrap(io.ErrUnexpectedEOF, "reading header")) != codes.DataLoss {
+		t.Error("foreign cause not classified")
+	}
+}
+
+func TestWithCode(t *testing.T) {
+	root := NotFound(nil, "no such user")
+	err := WithCode(root, codes.Internal)
+	if Code(err) != codes.Internal || !errors.Is(err, ErrInternal) || Cause(err) != root {
+		t.Errorf("got:%v", err)
+	}
+
+	if got := fmt.Sprint(err); got != "Internal] NotFound] no such user" {
+		t.Error(got)
+	}
+
+	if got := fmt.Sprint(WithCode(io.EOF, codes.InvalidArgument)); got != "InvalidArgument] EOF" {
+		t.Error(got)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	root := NotFound(nil, "no such user", "bob")
+	err := Annotate(root, Field("table", "users"), PublicMsg("unknown user"), RetryInfo{})
+
+	e, ok := err.(*eee)
+	if !ok || Cause(err) != nil || Desc(err) != "no such user" {
+		t.Fatalf("new error in the chain; got:%+v", err)
+	}
+
+	if !reflect.DeepEqual(e.args, []interface{}{"bob", F{"table", "users"}}) || !reflect.DeepEqual(root.(*eee).args, []interface{}{"bob"}) {
+		t.Errorf("got:%v root:%v", e.args, Args(root))
+	}
+
+	if Public(err) != "unknown user" || Public(root) != "not found" || len(AllDetails(err)) != 1 || AllDetails(root) != nil {
+		t.Error("markers not applied to the copy only")
+	}
+
+	if !reflect.DeepEqual(e.stack(), root.(*eee).stack()) {
+		t.Error("stacktrace changed")
+	}
+
+	wrapped := Annotate(io.EOF, Field("offset", 12))
+	if Code(wrapped) != codes.OutOfRange || Cause(wrapped) != io.EOF || wrapped.(*eee).callers != nil {
+		t.Errorf("got:%+v", wrapped)
+	}
+
+	if got := fmt.Sprintf("%+v", wrapped); got != "OutOfRange] offset=12\n  error] EOF" {
+		t.Errorf("got:%q", got)
+	}
+
+	calls := 0
+	SetHook(func(codes.Code, string) { calls++ })
+	defer SetHook(nil)
+
+	Annotate(io.EOF, Field("offset", 12))
+	Annotate(root, Field("offset", 12))
+	if calls != 0 {
+		t.Errorf("hook called for annotations: %d", calls)
+	}
+}